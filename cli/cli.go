@@ -0,0 +1,98 @@
+// Package cli is a small command framework for the minesweeper binary. Each
+// subcommand declares its flags on a flag.FlagSet, which lets the same
+// definitions drive argument parsing, help output, shell completions and the
+// man page.
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+type Command struct {
+	Name  string
+	Short string
+	// Hidden commands work normally but are left out of help and completions.
+	Hidden bool
+	// Flags holds the command's flags. It may be nil for commands without flags.
+	Flags *flag.FlagSet
+	// Run is called with the arguments left after flag parsing.
+	Run func(args []string) error
+}
+
+type App struct {
+	Name  string
+	Short string
+	// Default is the command run when no command name is given.
+	Default  string
+	Commands []*Command
+}
+
+// Execute parses args (without the program name) and runs the selected command.
+func (a *App) Execute(args []string) error {
+	name := a.Default
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" {
+		a.PrintUsage(os.Stdout)
+		return nil
+	}
+
+	cmd := a.Lookup(name)
+	if cmd == nil {
+		a.PrintUsage(os.Stderr)
+		return fmt.Errorf("unknown command %q", name)
+	}
+	if cmd.Flags != nil {
+		if err := cmd.Flags.Parse(args); err != nil {
+			return err
+		}
+		args = cmd.Flags.Args()
+	}
+	return cmd.Run(args)
+}
+
+// Lookup returns the command with the given name, or nil.
+func (a *App) Lookup(name string) *Command {
+	for _, cmd := range a.Commands {
+		if cmd.Name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// Visible returns the commands shown in help and completions.
+func (a *App) Visible() []*Command {
+	var visible []*Command
+	for _, cmd := range a.Commands {
+		if !cmd.Hidden {
+			visible = append(visible, cmd)
+		}
+	}
+	return visible
+}
+
+// PrintUsage writes the list of commands to w.
+func (a *App) PrintUsage(w io.Writer) {
+	fmt.Fprintf(w, "%s - %s\n\nUsage:\n  %s [command] [flags]\n\nCommands:\n", a.Name, a.Short, a.Name)
+	for _, cmd := range a.Visible() {
+		fmt.Fprintf(w, "  %-12s %s\n", cmd.Name, cmd.Short)
+	}
+	fmt.Fprintf(w, "\nRun '%s <command> -h' for the flags of a command.\n", a.Name)
+}
+
+// flagsOf returns the flags of cmd in lexical order.
+func flagsOf(cmd *Command) []*flag.Flag {
+	var flags []*flag.Flag
+	if cmd.Flags != nil {
+		cmd.Flags.VisitAll(func(f *flag.Flag) {
+			flags = append(flags, f)
+		})
+	}
+	return flags
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Completion returns a completion script for the given shell: bash, zsh or fish.
+func (a *App) Completion(shell string) (string, error) {
+	switch shell {
+	case "bash":
+		return a.bashCompletion(), nil
+	case "zsh":
+		return a.zshCompletion(), nil
+	case "fish":
+		return a.fishCompletion(), nil
+	default:
+		return "", fmt.Errorf("unsupported shell %q (want bash, zsh or fish)", shell)
+	}
+}
+
+func (a *App) bashCompletion() string {
+	var b strings.Builder
+	fn := "_" + a.Name
+	var names []string
+	for _, cmd := range a.Visible() {
+		names = append(names, cmd.Name)
+	}
+
+	fmt.Fprintf(&b, "# bash completion for %s\n", a.Name)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("\tlocal cur=${COMP_WORDS[COMP_CWORD]}\n")
+	b.WriteString("\tlocal cmd=${COMP_WORDS[1]}\n")
+	b.WriteString("\tif [ \"$COMP_CWORD\" -eq 1 ] && [[ \"$cur\" != -* ]]; then\n")
+	fmt.Fprintf(&b, "\t\tCOMPREPLY=( $(compgen -W %q -- \"$cur\") )\n", strings.Join(names, " "))
+	b.WriteString("\t\treturn\n\tfi\n")
+	// Flags typed straight after the program name belong to the default command.
+	fmt.Fprintf(&b, "\tif [[ \"$cmd\" == -* ]]; then\n\t\tcmd=%s\n\tfi\n", a.Default)
+	b.WriteString("\tcase \"$cmd\" in\n")
+	for _, cmd := range a.Visible() {
+		var flags []string
+		for _, f := range flagsOf(cmd) {
+			flags = append(flags, "--"+f.Name)
+		}
+		fmt.Fprintf(&b, "\t%s)\n\t\tCOMPREPLY=( $(compgen -W %q -- \"$cur\") )\n\t\t;;\n", cmd.Name, strings.Join(flags, " "))
+	}
+	b.WriteString("\tesac\n}\n")
+	fmt.Fprintf(&b, "complete -F %s %s\n", fn, a.Name)
+	return b.String()
+}
+
+func (a *App) zshCompletion() string {
+	var b strings.Builder
+	fn := "_" + a.Name
+
+	fmt.Fprintf(&b, "#compdef %s\n\n", a.Name)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("\tlocal -a commands\n\tcommands=(\n")
+	for _, cmd := range a.Visible() {
+		fmt.Fprintf(&b, "\t\t'%s:%s'\n", cmd.Name, zshEscape(cmd.Short))
+	}
+	b.WriteString("\t)\n")
+	b.WriteString("\tif (( CURRENT == 2 )) && [[ $words[2] != -* ]]; then\n")
+	b.WriteString("\t\t_describe 'command' commands\n\t\treturn\n\tfi\n")
+	b.WriteString("\tlocal cmd=$words[2]\n")
+	fmt.Fprintf(&b, "\t[[ $cmd == -* ]] && cmd=%s\n", a.Default)
+	b.WriteString("\tcase $cmd in\n")
+	for _, cmd := range a.Visible() {
+		fmt.Fprintf(&b, "\t%s)\n\t\t_arguments", cmd.Name)
+		for _, f := range flagsOf(cmd) {
+			// A trailing ':' makes zsh expect a value after the flag.
+			value := ":"
+			if isBoolFlag(f) {
+				value = ""
+			}
+			fmt.Fprintf(&b, " '--%s[%s]%s'", f.Name, zshEscape(f.Usage), value)
+		}
+		b.WriteString(" '*::'\n\t\t;;\n")
+	}
+	b.WriteString("\tesac\n}\n\n")
+	fmt.Fprintf(&b, "%s \"$@\"\n", fn)
+	return b.String()
+}
+
+func (a *App) fishCompletion() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# fish completion for %s\n", a.Name)
+	fmt.Fprintf(&b, "complete -c %s -f\n", a.Name)
+	for _, cmd := range a.Visible() {
+		fmt.Fprintf(&b, "complete -c %s -n __fish_use_subcommand -a %s -d %s\n", a.Name, cmd.Name, fishQuote(cmd.Short))
+		for _, f := range flagsOf(cmd) {
+			condition := "__fish_seen_subcommand_from " + cmd.Name
+			if cmd.Name == a.Default {
+				condition = "__fish_use_subcommand; or " + condition
+			}
+			// -r makes fish expect a value after the flag.
+			value := " -r"
+			if isBoolFlag(f) {
+				value = ""
+			}
+			fmt.Fprintf(&b, "complete -c %s -n %s -l %s%s -d %s\n", a.Name, fishQuote(condition), f.Name, value, fishQuote(f.Usage))
+		}
+	}
+	return b.String()
+}
+
+// ManPage returns a roff man page (section 6, games) describing every visible
+// command and its flags.
+func (a *App) ManPage(version string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, ".TH %s 6 %q %q \"Games\"\n", strings.ToUpper(a.Name), time.Now().Format("2006-01-02"), a.Name+" "+version)
+	fmt.Fprintf(&b, ".SH NAME\n%s \\- %s\n", a.Name, roffEscape(a.Short))
+	fmt.Fprintf(&b, ".SH SYNOPSIS\n.B %s\n[\\fIcommand\\fR] [\\fIflags\\fR]\n", a.Name)
+	fmt.Fprintf(&b, ".SH DESCRIPTION\nWithout a command, \\fB%s\\fR runs \\fB%s\\fR.\n", a.Name, a.Default)
+	b.WriteString(".SH COMMANDS\n")
+	for _, cmd := range a.Visible() {
+		fmt.Fprintf(&b, ".TP\n.B %s\n%s\n", cmd.Name, roffEscape(cmd.Short))
+		for _, f := range flagsOf(cmd) {
+			usage := roffEscape(f.Usage)
+			if f.DefValue != "" && f.DefValue != "false" {
+				usage += fmt.Sprintf(" (default \\fI%s\\fR)", roffEscape(f.DefValue))
+			}
+			fmt.Fprintf(&b, ".RS\n.TP\n.B \\-\\-%s\n%s\n.RE\n", roffEscape(f.Name), usage)
+		}
+	}
+	return b.String()
+}
+
+// isBoolFlag reports whether f is a switch that takes no value, such as
+// the flags defined with flag.Bool.
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+func zshEscape(s string) string {
+	s = strings.ReplaceAll(s, "'", "'\\''")
+	s = strings.ReplaceAll(s, "[", "\\[")
+	s = strings.ReplaceAll(s, "]", "\\]")
+	return strings.ReplaceAll(s, ":", "\\:")
+}
+
+func fishQuote(s string) string {
+	return "'" + strings.ReplaceAll(strings.ReplaceAll(s, "\\", "\\\\"), "'", "\\'") + "'"
+}
+
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\e")
+	s = strings.ReplaceAll(s, "-", "\\-")
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = "\\&" + s
+	}
+	return s
+}
@@ -0,0 +1,201 @@
+// Package config resolves the game settings. Each setting is taken from the
+// first source that provides it, in this order of precedence:
+//
+//  1. command-line flags (applied by the caller on top of Load's result);
+//  2. environment variables (MINESWEEPER_*);
+//  3. built-in defaults.
+//
+// Supported environment variables:
+//
+//	MINESWEEPER_LEVEL          level 1-6 to start without the level prompt
+//	MINESWEEPER_SEED           board seed; 0 picks a random one
+//	MINESWEEPER_SAFE_START     "true" or "false"; place mines after the first reveal
+//	MINESWEEPER_NO_GUESS       "true" or "false"; only generate boards solvable without guessing
+//	MINESWEEPER_CONFIRM_FLAGS  confirm quick reveals next to this many flags; 0 disables
+//	MINESWEEPER_THEME          board theme: classic, dark or high-contrast
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+const envPrefix = "MINESWEEPER_"
+
+type Config struct {
+	// Level is the difficulty from 1 to 6, or 0 to ask the player.
+	Level int
+	// Rows, Cols and Mines override the board of the level when non-zero.
+	Rows  int
+	Cols  int
+	Mines int
+	// SafeStart keeps the first revealed cell and its neighbors free of mines.
+	SafeStart bool
+	// NoGuess only generates boards that can be cleared without guessing.
+	NoGuess bool
+	// Seed generates a reproducible board, or 0 for a random one.
+	Seed int64
+	// ConfirmFlags asks for a second Enter before revealing a cell next to at
+	// least this many flags within ConfirmWindow of the previous key, or 0 to
+	// never ask.
+	ConfirmFlags  int
+	ConfirmWindow time.Duration
+	// Theme is the name of the theme the board is drawn with.
+	Theme string
+}
+
+// Default returns the built-in settings.
+func Default() Config {
+	return Config{
+		Level:         0,
+		SafeStart:     true,
+		ConfirmWindow: 300 * time.Millisecond,
+		Theme:         "classic",
+	}
+}
+
+// Load returns the defaults overridden by any MINESWEEPER_* environment
+// variables. An invalid value is reported as an error naming the variable.
+func Load() (Config, error) {
+	cfg := Default()
+
+	if value, ok := lookup("LEVEL"); ok {
+		level, err := strconv.Atoi(value)
+		if err != nil || level < 1 || level > 6 {
+			return cfg, fmt.Errorf("%sLEVEL: want a level between 1 and 6, got %q", envPrefix, value)
+		}
+		cfg.Level = level
+	}
+
+	if value, ok := lookup("SEED"); ok {
+		seed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return cfg, fmt.Errorf("%sSEED: want an integer, got %q", envPrefix, value)
+		}
+		cfg.Seed = seed
+	}
+
+	if value, ok := lookup("SAFE_START"); ok {
+		safe, err := strconv.ParseBool(value)
+		if err != nil {
+			return cfg, fmt.Errorf("%sSAFE_START: want true or false, got %q", envPrefix, value)
+		}
+		cfg.SafeStart = safe
+	}
+
+	if value, ok := lookup("NO_GUESS"); ok {
+		noGuess, err := strconv.ParseBool(value)
+		if err != nil {
+			return cfg, fmt.Errorf("%sNO_GUESS: want true or false, got %q", envPrefix, value)
+		}
+		cfg.NoGuess = noGuess
+	}
+
+	if value, ok := lookup("CONFIRM_FLAGS"); ok {
+		flags, err := strconv.Atoi(value)
+		if err != nil || flags < 0 || flags > 8 {
+			return cfg, fmt.Errorf("%sCONFIRM_FLAGS: want a number between 0 and 8, got %q", envPrefix, value)
+		}
+		cfg.ConfirmFlags = flags
+	}
+
+	if value, ok := lookup("THEME"); ok {
+		cfg.Theme = value
+	}
+
+	return cfg, nil
+}
+
+// lookup returns the value of the MINESWEEPER_<name> variable if it is set
+// and not empty.
+func lookup(name string) (string, bool) {
+	value, ok := os.LookupEnv(envPrefix + name)
+	return value, ok && value != ""
+}
+
+// Flags holds the command-line overrides of a Config.
+type Flags struct {
+	set       *flag.FlagSet
+	level     *int
+	rows      *int
+	cols      *int
+	mines     *int
+	safeStart *bool
+	noGuess   *bool
+	seed      *int64
+	confirm   *int
+	window    *time.Duration
+	theme     *string
+}
+
+// NewFlags registers the game settings as flags on fs.
+func NewFlags(fs *flag.FlagSet) *Flags {
+	defaults := Default()
+	return &Flags{
+		set:       fs,
+		level:     fs.Int("level", defaults.Level, "level from 1 to 6; skips the level prompt"),
+		rows:      fs.Int("rows", defaults.Rows, "number of board rows, overriding the level"),
+		cols:      fs.Int("cols", defaults.Cols, "number of board columns, overriding the level"),
+		mines:     fs.Int("mines", defaults.Mines, "number of mines, overriding the level"),
+		safeStart: fs.Bool("safe-start", defaults.SafeStart, "place mines after the first reveal so it is always safe"),
+		noGuess:   fs.Bool("no-guess", defaults.NoGuess, "only generate boards that can be cleared without guessing"),
+		seed:      fs.Int64("seed", defaults.Seed, "seed for a reproducible board; 0 picks a random one"),
+		confirm:   fs.Int("confirm-flags", defaults.ConfirmFlags, "ask for a second Enter before quickly revealing a cell next to this many flags; 0 disables"),
+		theme:     fs.String("theme", defaults.Theme, "board theme: classic, dark or high-contrast"),
+		window:    fs.Duration("confirm-window", defaults.ConfirmWindow, "how soon after the previous key a reveal counts as quick for --confirm-flags"),
+	}
+}
+
+// Apply copies the flags given on the command line into cfg. Flags that were
+// not given leave the environment or default value in place.
+func (f *Flags) Apply(cfg *Config) {
+	f.set.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "level":
+			cfg.Level = *f.level
+		case "rows":
+			cfg.Rows = *f.rows
+		case "cols":
+			cfg.Cols = *f.cols
+		case "mines":
+			cfg.Mines = *f.mines
+		case "safe-start":
+			cfg.SafeStart = *f.safeStart
+		case "no-guess":
+			cfg.NoGuess = *f.noGuess
+		case "seed":
+			cfg.Seed = *f.seed
+		case "confirm-flags":
+			cfg.ConfirmFlags = *f.confirm
+		case "confirm-window":
+			cfg.ConfirmWindow = *f.window
+		case "theme":
+			cfg.Theme = *f.theme
+		}
+	})
+}
+
+// Validate reports settings that are out of range.
+func (c Config) Validate() error {
+	if c.Level < 0 || c.Level > 6 {
+		return fmt.Errorf("level must be between 1 and 6, got %d", c.Level)
+	}
+	if c.Rows < 0 || c.Cols < 0 || c.Mines < 0 {
+		return fmt.Errorf("rows, cols and mines must not be negative")
+	}
+	if c.ConfirmFlags < 0 || c.ConfirmFlags > 8 {
+		return fmt.Errorf("confirm-flags must be between 0 and 8, got %d", c.ConfirmFlags)
+	}
+	if c.ConfirmWindow < 0 {
+		return fmt.Errorf("confirm-window must not be negative")
+	}
+	return nil
+}
+
+// CustomBoard reports whether the board size or mine count was set explicitly.
+func (c Config) CustomBoard() bool {
+	return c.Rows > 0 || c.Cols > 0 || c.Mines > 0
+}
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// setEnv sets the given MINESWEEPER_* variables for the test and clears all
+// others, so the settings of whoever runs the tests do not leak in.
+func setEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, name := range []string{"LEVEL", "SEED", "SAFE_START", "NO_GUESS", "CONFIRM_FLAGS", "THEME"} {
+		t.Setenv(envPrefix+name, env[name])
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want func(cfg *Config)
+	}{
+		{
+			name: "defaults",
+			want: func(cfg *Config) {},
+		},
+		{
+			name: "environment overrides defaults",
+			env: map[string]string{
+				"LEVEL":         "3",
+				"SEED":          "-42",
+				"SAFE_START":    "false",
+				"NO_GUESS":      "true",
+				"CONFIRM_FLAGS": "2",
+				"THEME":         "dark",
+			},
+			want: func(cfg *Config) {
+				cfg.Level = 3
+				cfg.Seed = -42
+				cfg.SafeStart = false
+				cfg.NoGuess = true
+				cfg.ConfirmFlags = 2
+				cfg.Theme = "dark"
+			},
+		},
+		{
+			name: "empty variables are unset",
+			env:  map[string]string{"LEVEL": "", "SAFE_START": ""},
+			want: func(cfg *Config) {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, tt.env)
+			want := Default()
+			tt.want(&want)
+			cfg, err := Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(cfg, want) {
+				t.Errorf("Load = %+v, want %+v", cfg, want)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		name, value string
+	}{
+		{"LEVEL", "0"},
+		{"LEVEL", "7"},
+		{"LEVEL", "easy"},
+		{"SEED", "1.5"},
+		{"SAFE_START", "maybe"},
+		{"NO_GUESS", "yes please"},
+		{"CONFIRM_FLAGS", "-1"},
+		{"CONFIRM_FLAGS", "9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+"="+tt.value, func(t *testing.T) {
+			setEnv(t, map[string]string{tt.name: tt.value})
+			if _, err := Load(); err == nil {
+				t.Errorf("Load accepted %s%s=%q", envPrefix, tt.name, tt.value)
+			}
+		})
+	}
+}
+
+func TestFlagsApply(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want func(cfg *Config)
+	}{
+		{
+			name: "no flags keep the environment",
+			env:  map[string]string{"LEVEL": "2", "THEME": "dark"},
+			want: func(cfg *Config) {
+				cfg.Level = 2
+				cfg.Theme = "dark"
+			},
+		},
+		{
+			name: "flags override the environment",
+			env:  map[string]string{"LEVEL": "2", "SAFE_START": "false", "THEME": "dark"},
+			args: []string{"--level", "5", "--safe-start", "--theme", "high-contrast"},
+			want: func(cfg *Config) {
+				cfg.Level = 5
+				cfg.SafeStart = true
+				cfg.Theme = "high-contrast"
+			},
+		},
+		{
+			// A flag given with its default value still wins over the environment.
+			name: "flag set to the default",
+			env:  map[string]string{"SEED": "7", "NO_GUESS": "true"},
+			args: []string{"--seed", "0", "--no-guess=false"},
+			want: func(cfg *Config) {},
+		},
+		{
+			name: "flags without a variable",
+			args: []string{"--rows", "9", "--cols", "12", "--mines", "20", "--confirm-window", "1s"},
+			want: func(cfg *Config) {
+				cfg.Rows, cfg.Cols, cfg.Mines = 9, 12, 20
+				cfg.ConfirmWindow = time.Second
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, tt.env)
+			fs := flag.NewFlagSet("play", flag.ContinueOnError)
+			flags := NewFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			flags.Apply(&cfg)
+
+			want := Default()
+			tt.want(&want)
+			if !reflect.DeepEqual(cfg, want) {
+				t.Errorf("config = %+v, want %+v", cfg, want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(cfg *Config)
+		wantErr bool
+	}{
+		{"defaults", func(cfg *Config) {}, false},
+		{"custom board", func(cfg *Config) { cfg.Rows, cfg.Cols, cfg.Mines = 5, 5, 3 }, false},
+		{"level too high", func(cfg *Config) { cfg.Level = 7 }, true},
+		{"negative level", func(cfg *Config) { cfg.Level = -1 }, true},
+		{"negative rows", func(cfg *Config) { cfg.Rows = -1 }, true},
+		{"negative mines", func(cfg *Config) { cfg.Mines = -1 }, true},
+		{"too many confirm flags", func(cfg *Config) { cfg.ConfirmFlags = 9 }, true},
+		{"negative confirm window", func(cfg *Config) { cfg.ConfirmWindow = -time.Second }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.change(&cfg)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// Package engine implements the rules of minesweeper on top of a
+// models.Minesweeper board: revealing cells with flood fill, chording,
+// flagging and detecting a win or a loss. It has no terminal dependencies,
+// so the game can be driven by any frontend or from tests.
+package engine
+
+import "github.com/dimaq12/minesweaper/models"
+
+// Status is the state of a game.
+type Status int
+
+const (
+	Playing Status = iota
+	Won
+	Lost
+)
+
+// Game applies player actions to a board. Every method locks the board's Mu
+// itself, so a Game can be used from several goroutines.
+type Game struct {
+	Board *models.Minesweeper
+	Mines int
+}
+
+func New(board *models.Minesweeper, mines int) *Game {
+	return &Game{Board: board, Mines: mines}
+}
+
+// IsShown reports whether the cell at row, col is valid and already shown.
+func (g *Game) IsShown(row, col int) bool {
+	if !g.Board.InBounds(row, col) {
+		return false
+	}
+	g.Board.Mu.Lock()
+	defer g.Board.Mu.Unlock()
+	return g.Board.Board[row][col].IsShown
+}
+
+// Reveal takes a cell's row and col coordinates as input and shows the cell,
+// updating its IsShown state and the number of nearby mines. If the shown
+// cell has zero nearby mines, all neighboring cells that are not already
+// shown are revealed as well.
+func (g *Game) Reveal(row, col int) {
+	g.Board.Mu.Lock()
+	defer g.Board.Mu.Unlock()
+	g.reveal(row, col)
+}
+
+// reveal is the flood fill behind Reveal. The caller is expected to hold Mu.
+func (g *Game) reveal(row, col int) {
+	// Check if the given row and col are within the borders of the game board,
+	// and if the cell is already shown or flagged. If any of these conditions
+	// is true, the function returns immediately without revealing the cell.
+	// A flagged cell also stops the flood fill, which would otherwise go back
+	// and forth between two flagged cells without nearby mines.
+	if !g.Board.InBounds(row, col) || g.Board.Board[row][col].IsShown || g.Board.Board[row][col].IsFlagged {
+		return
+	}
+
+	// Set the cell's IsShown property to true, indicating that it has been shown.
+	g.Board.Board[row][col].IsShown = true
+
+	// Update the cell's nearbyMines property with the count of nearby mines.
+	g.Board.Board[row][col].NearbyMines = g.Board.CountNearbyMines(row, col)
+
+	// If the shown cell is safe and has no nearby mines (i.e., nearbyMines
+	// is 0), recursively reveal all neighboring cells.
+	if !g.Board.Board[row][col].IsMine && g.Board.Board[row][col].NearbyMines == 0 {
+		// Loop through all neighboring cells using relative row (deltaRow) and column (deltaCol) offsets.
+		for deltaRow := -1; deltaRow <= 1; deltaRow++ {
+			for deltaCol := -1; deltaCol <= 1; deltaCol++ {
+				// Skip the current cell 0, 0
+				if deltaRow == 0 && deltaCol == 0 {
+					continue
+				}
+				// Recursively call reveal for the neighboring cell.
+				g.reveal(row+deltaRow, col+deltaCol)
+			}
+		}
+	}
+}
+
+// Chord takes the coordinates of an already shown cell and, if the number
+// of flags around it equals its number of nearby mines, shows all of its
+// remaining unflagged neighbors at once. It reports whether it did.
+func (g *Game) Chord(row, col int) bool {
+	if !g.Board.InBounds(row, col) {
+		return false
+	}
+	g.Board.Mu.Lock()
+	defer g.Board.Mu.Unlock()
+
+	// Count the adjacent flags and collect the neighbors that would be shown.
+	cell := g.Board.Board[row][col]
+	flags := 0
+	var targets [][2]int
+	for deltaRow := -1; deltaRow <= 1; deltaRow++ {
+		for deltaCol := -1; deltaCol <= 1; deltaCol++ {
+			if deltaRow == 0 && deltaCol == 0 {
+				continue
+			}
+			newRow, newCol := row+deltaRow, col+deltaCol
+			if !g.Board.InBounds(newRow, newCol) {
+				continue
+			}
+			neighbor := g.Board.Board[newRow][newCol]
+			if neighbor.IsShown {
+				continue
+			}
+			if neighbor.IsFlagged {
+				flags++
+			} else {
+				targets = append(targets, [2]int{newRow, newCol})
+			}
+		}
+	}
+
+	// Chording only works on a shown number whose mines are all flagged.
+	if !cell.IsShown || cell.IsMine || flags != cell.NearbyMines {
+		return false
+	}
+
+	for _, target := range targets {
+		g.reveal(target[0], target[1])
+	}
+	return true
+}
+
+// ToggleFlag cycles the cell through unmarked, flagged and question-marked.
+func (g *Game) ToggleFlag(row, col int) {
+	if !g.Board.InBounds(row, col) {
+		return
+	}
+	g.Board.Mu.Lock()
+	defer g.Board.Mu.Unlock()
+	cell := &g.Board.Board[row][col]
+	switch {
+	case cell.IsFlagged:
+		cell.IsFlagged = false
+		cell.IsQuestioned = true
+	case cell.IsQuestioned:
+		cell.IsQuestioned = false
+	default:
+		cell.IsFlagged = true
+	}
+}
+
+// FlagObviousMines flags the hidden neighbors of every shown number that has
+// exactly as many hidden neighbors as nearby mines.
+func (g *Game) FlagObviousMines() {
+	g.Board.Mu.Lock()
+	defer g.Board.Mu.Unlock()
+	for row := 0; row < g.Board.Rows; row++ {
+		for col := 0; col < g.Board.Cols; col++ {
+			cell := g.Board.Board[row][col]
+			if !cell.IsShown || cell.IsMine || cell.NearbyMines == 0 {
+				continue
+			}
+
+			var hidden [][2]int
+			for deltaRow := -1; deltaRow <= 1; deltaRow++ {
+				for deltaCol := -1; deltaCol <= 1; deltaCol++ {
+					newRow, newCol := row+deltaRow, col+deltaCol
+					if g.Board.InBounds(newRow, newCol) && !g.Board.Board[newRow][newCol].IsShown {
+						hidden = append(hidden, [2]int{newRow, newCol})
+					}
+				}
+			}
+			if len(hidden) != cell.NearbyMines {
+				continue
+			}
+			for _, target := range hidden {
+				neighbor := &g.Board.Board[target[0]][target[1]]
+				neighbor.IsFlagged = true
+				neighbor.IsQuestioned = false
+			}
+		}
+	}
+}
+
+// Exploded returns the position of a shown mine, i.e. the one that ended a
+// lost game, if there is one.
+func (g *Game) Exploded() (row, col int, ok bool) {
+	g.Board.Mu.Lock()
+	defer g.Board.Mu.Unlock()
+	for row := 0; row < g.Board.Rows; row++ {
+		for col := 0; col < g.Board.Cols; col++ {
+			if cell := g.Board.Board[row][col]; cell.IsShown && cell.IsMine {
+				return row, col, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// RevealAll shows every cell on the board, e.g. once the game is over.
+func (g *Game) RevealAll() {
+	g.Board.Mu.Lock()
+	defer g.Board.Mu.Unlock()
+	for row := 0; row < g.Board.Rows; row++ {
+		for col := 0; col < g.Board.Cols; col++ {
+			g.Board.Board[row][col].IsShown = true
+			g.Board.Board[row][col].NearbyMines = g.Board.CountNearbyMines(row, col)
+		}
+	}
+}
+
+// Status reports whether the game is still being played, won or lost.
+func (g *Game) Status() Status {
+	g.Board.Mu.Lock()
+	defer g.Board.Mu.Unlock()
+
+	shownNonMineCells := 0
+	for row := 0; row < g.Board.Rows; row++ {
+		for col := 0; col < g.Board.Cols; col++ {
+			cell := g.Board.Board[row][col]
+			if cell.IsShown {
+				if cell.IsMine {
+					// If a shown cell is a mine, the player has lost.
+					return Lost
+				}
+				shownNonMineCells++
+			}
+		}
+	}
+
+	// If all non-mine cells are shown, the player has won.
+	if g.Board.Rows*g.Board.Cols-shownNonMineCells == g.Mines {
+		return Won
+	}
+
+	// The game is still ongoing.
+	return Playing
+}
@@ -0,0 +1,276 @@
+package engine
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dimaq12/minesweaper/models"
+)
+
+// newGame builds a game from a layout with one string per row, '*' marking
+// a mine and 'F' a flagged cell without one.
+func newGame(layout ...string) *Game {
+	board := models.NewMinesweeper(len(layout), len(layout[0]))
+	mines := 0
+	for row, line := range layout {
+		for col, c := range line {
+			switch c {
+			case '*':
+				board.Board[row][col].IsMine = true
+				mines++
+			case 'F':
+				board.Board[row][col].IsFlagged = true
+			}
+		}
+	}
+	return New(board, mines)
+}
+
+// view draws what the player sees: '#' for a hidden cell, 'F' and '?' for
+// marked ones, '*' for a shown mine and the number of nearby mines otherwise.
+func view(g *Game) []string {
+	lines := make([]string, g.Board.Rows)
+	for row := range lines {
+		var line strings.Builder
+		for _, cell := range g.Board.Board[row] {
+			switch {
+			case cell.IsShown && cell.IsMine:
+				line.WriteByte('*')
+			case cell.IsShown:
+				line.WriteByte(byte('0' + cell.NearbyMines))
+			case cell.IsFlagged:
+				line.WriteByte('F')
+			case cell.IsQuestioned:
+				line.WriteByte('?')
+			default:
+				line.WriteByte('#')
+			}
+		}
+		lines[row] = line.String()
+	}
+	return lines
+}
+
+func TestReveal(t *testing.T) {
+	tests := []struct {
+		name     string
+		layout   []string
+		row, col int
+		want     []string
+	}{
+		{
+			name:   "flood fill stops at numbers",
+			layout: []string{"....", "....", "...*", "...."},
+			row:    0, col: 0,
+			want: []string{"0000", "0011", "001#", "001#"},
+		},
+		{
+			name:   "number shows only itself",
+			layout: []string{"....", "....", "...*", "...."},
+			row:    1, col: 2,
+			want: []string{"####", "##1#", "####", "####"},
+		},
+		{
+			name:   "flood fill keeps flags",
+			layout: []string{"...F", "....", "...*", "...."},
+			row:    0, col: 0,
+			want: []string{"000F", "0011", "001#", "001#"},
+		},
+		{
+			// Two flagged cells without nearby mines used to hand the flood
+			// fill back and forth forever.
+			name:   "flood fill stops at flags",
+			layout: []string{"..FF", "....", "....", "...*"},
+			row:    0, col: 0,
+			want: []string{"00FF", "0000", "0011", "001#"},
+		},
+		{
+			name:   "flagged cell",
+			layout: []string{"F...", "....", "...*"},
+			row:    0, col: 0,
+			want: []string{"F###", "####", "####"},
+		},
+		{
+			name:   "mine without nearby mines",
+			layout: []string{"*..", "...", "..."},
+			row:    0, col: 0,
+			want: []string{"*##", "###", "###"},
+		},
+		{
+			name:   "out of bounds",
+			layout: []string{"*..", "...", "..."},
+			row:    3, col: -1,
+			want: []string{"###", "###", "###"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGame(tt.layout...)
+			g.Reveal(tt.row, tt.col)
+			if got := view(g); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("board = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChord(t *testing.T) {
+	// The middle cell of the top row is a 1 touching the mine on its left.
+	layout := []string{"*..", "...", "..."}
+	tests := []struct {
+		name  string
+		flags [][2]int
+		row   int
+		col   int
+		ok    bool
+		want  []string
+	}{
+		{
+			name:  "flags match",
+			flags: [][2]int{{0, 0}},
+			row:   0, col: 1,
+			ok:   true,
+			want: []string{"F10", "110", "000"},
+		},
+		{
+			name: "too few flags",
+			row:  0, col: 1,
+			want: []string{"#1#", "###", "###"},
+		},
+		{
+			name:  "too many flags",
+			flags: [][2]int{{0, 0}, {1, 1}},
+			row:   0, col: 1,
+			want: []string{"F1#", "#F#", "###"},
+		},
+		{
+			name:  "wrong flag",
+			flags: [][2]int{{1, 0}},
+			row:   0, col: 1,
+			ok:   true,
+			want: []string{"*10", "F10", "000"},
+		},
+		{
+			name:  "hidden cell",
+			flags: [][2]int{{0, 0}},
+			row:   1, col: 1,
+			want: []string{"F1#", "###", "###"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGame(layout...)
+			g.Reveal(0, 1)
+			for _, flag := range tt.flags {
+				g.ToggleFlag(flag[0], flag[1])
+			}
+			if ok := g.Chord(tt.row, tt.col); ok != tt.ok {
+				t.Errorf("Chord = %v, want %v", ok, tt.ok)
+			}
+			if got := view(g); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("board = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToggleFlag(t *testing.T) {
+	g := newGame("*.", "..")
+	for _, want := range []string{"F#", "?#", "##", "F#"} {
+		g.ToggleFlag(0, 0)
+		if got := view(g)[0]; got != want {
+			t.Fatalf("row = %q, want %q", got, want)
+		}
+	}
+
+	// Out of bounds is ignored.
+	g.ToggleFlag(2, 2)
+}
+
+func TestFlagObviousMines(t *testing.T) {
+	tests := []struct {
+		name     string
+		layout   []string
+		row, col int
+		want     []string
+	}{
+		{
+			// The 1 has more hidden neighbors than mines.
+			name:   "nothing obvious",
+			layout: []string{"*...", "....", "....", "...."},
+			row:    0, col: 1,
+			want: []string{"#1##", "####", "####", "####"},
+		},
+		{
+			name:   "number with as many hidden neighbors as mines",
+			layout: []string{"*...", "....", "....", "...."},
+			row:    3, col: 3,
+			want: []string{"F100", "1100", "0000", "0000"},
+		},
+		{
+			name:   "only the numbers that are certain",
+			layout: []string{"*..", "*..", "..."},
+			row:    2, col: 2,
+			want: []string{"F20", "F20", "#10"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGame(tt.layout...)
+			g.Reveal(tt.row, tt.col)
+			g.FlagObviousMines()
+			if got := view(g); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("board = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlagObviousMinesReplacesQuestionMarks(t *testing.T) {
+	g := newGame("*..", "*..", "...")
+	g.ToggleFlag(0, 0)
+	g.ToggleFlag(0, 0)
+	g.Reveal(2, 2)
+	g.FlagObviousMines()
+	if cell := g.Board.Board[0][0]; !cell.IsFlagged || cell.IsQuestioned {
+		t.Errorf("cell = %+v, want flagged and not questioned", cell)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout []string
+		reveal [][2]int
+		want   Status
+	}{
+		{"untouched", []string{"*.", ".."}, nil, Playing},
+		{"partly revealed", []string{"*.", ".."}, [][2]int{{0, 1}}, Playing},
+		{"all safe cells shown", []string{"*.", ".."}, [][2]int{{0, 1}, {1, 0}, {1, 1}}, Won},
+		{"flags do not matter", []string{"*F", ".."}, [][2]int{{1, 0}, {1, 1}}, Playing},
+		{"mine shown", []string{"*.", ".."}, [][2]int{{0, 1}, {0, 0}}, Lost},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGame(tt.layout...)
+			for _, cell := range tt.reveal {
+				g.Reveal(cell[0], cell[1])
+			}
+			if got := g.Status(); got != tt.want {
+				t.Errorf("Status = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExploded(t *testing.T) {
+	g := newGame("..", ".*")
+	if _, _, ok := g.Exploded(); ok {
+		t.Error("Exploded reported a mine before any was shown")
+	}
+	g.Reveal(1, 1)
+	if row, col, ok := g.Exploded(); !ok || row != 1 || col != 1 {
+		t.Errorf("Exploded = %d, %d, %v, want 1, 1, true", row, col, ok)
+	}
+}
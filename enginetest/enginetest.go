@@ -0,0 +1,59 @@
+// Package enginetest provides reusable invariant checkers for the game
+// model, so rule changes and variants can be validated from a test with a
+// single call:
+//
+//	enginetest.AssertInvariants(t, game, mineQuantity)
+package enginetest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dimaq12/minesweaper/models"
+)
+
+// CheckInvariants returns an error describing the first broken invariant of
+// the board, or nil if the board is consistent:
+//   - the board has exactly mineQuantity mines, or none while no cell is
+//     shown yet, since first-click safety only places them on the first reveal;
+//   - every shown safe cell reports the recomputed number of nearby mines;
+//   - the board dimensions match Rows and Cols.
+func CheckInvariants(game *models.Minesweeper, mineQuantity int) error {
+	game.Mu.Lock()
+	defer game.Mu.Unlock()
+
+	if len(game.Board) != game.Rows {
+		return fmt.Errorf("board has %d rows, want %d", len(game.Board), game.Rows)
+	}
+	for row := range game.Board {
+		if len(game.Board[row]) != game.Cols {
+			return fmt.Errorf("row %d has %d cols, want %d", row, len(game.Board[row]), game.Cols)
+		}
+	}
+
+	if mines, shown, _ := game.Counts(); mines != mineQuantity && !(mines == 0 && shown == 0) {
+		return fmt.Errorf("board has %d mines, want %d", mines, mineQuantity)
+	}
+
+	for row := 0; row < game.Rows; row++ {
+		for col := 0; col < game.Cols; col++ {
+			cell := game.Board[row][col]
+			if !cell.IsShown || cell.IsMine {
+				continue
+			}
+			if want := game.CountNearbyMines(row, col); cell.NearbyMines != want {
+				return fmt.Errorf("cell (%d, %d) shows %d nearby mines, want %d", row, col, cell.NearbyMines, want)
+			}
+		}
+	}
+
+	return nil
+}
+
+// AssertInvariants fails the test if CheckInvariants reports a broken invariant.
+func AssertInvariants(t testing.TB, game *models.Minesweeper, mineQuantity int) {
+	t.Helper()
+	if err := CheckInvariants(game, mineQuantity); err != nil {
+		t.Fatalf("enginetest: %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package enginetest_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/dimaq12/minesweaper/engine"
+	"github.com/dimaq12/minesweaper/enginetest"
+	"github.com/dimaq12/minesweaper/models"
+)
+
+const (
+	rows, cols, mines = 8, 8, 10
+	seed              = 42
+)
+
+// newGame returns a game on the board seed 42 yields, which is:
+//
+//	..*.....
+//	........
+//	*......*
+//	...*....
+//	........
+//	..**.*..
+//	.......*
+//	..*...*.
+func newGame() *engine.Game {
+	board := models.NewMinesweeper(rows, cols)
+	board.PlaceMinesWithSeed(seed, mines)
+	return engine.New(board, mines)
+}
+
+// step is one player action, applied through engine.Game.
+type step struct {
+	action   string
+	row, col int
+}
+
+func (s step) apply(game *engine.Game) {
+	switch s.action {
+	case "reveal":
+		game.Reveal(s.row, s.col)
+	case "chord":
+		game.Chord(s.row, s.col)
+	case "flag":
+		game.ToggleFlag(s.row, s.col)
+	case "auto-flag":
+		game.FlagObviousMines()
+	}
+}
+
+func TestInvariantsHoldDuringPlay(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"flood fill from a corner", []step{{"reveal", 0, 0}}},
+		{"reveal a number", []step{{"reveal", 1, 2}}},
+		{"flag cycle", []step{{"flag", 0, 2}, {"flag", 0, 2}, {"flag", 0, 2}}},
+		{"flag then chord", []step{
+			{"reveal", 0, 0},
+			{"flag", 0, 2},
+			{"chord", 1, 1},
+			{"chord", 1, 3},
+		}},
+		{"chord with a wrong flag", []step{
+			{"reveal", 1, 2},
+			{"flag", 1, 3},
+			{"chord", 1, 2},
+		}},
+		{"auto-flag after a reveal", []step{{"reveal", 0, 0}, {"auto-flag", 0, 0}}},
+		{"step on a mine", []step{{"reveal", 0, 0}, {"reveal", 2, 0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := newGame()
+			enginetest.AssertInvariants(t, game.Board, mines)
+			for _, s := range tt.steps {
+				s.apply(game)
+				enginetest.AssertInvariants(t, game.Board, mines)
+			}
+		})
+	}
+}
+
+func TestInvariantsHoldUntilWon(t *testing.T) {
+	game := newGame()
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			if !game.Board.Board[row][col].IsMine {
+				game.Reveal(row, col)
+				enginetest.AssertInvariants(t, game.Board, mines)
+			}
+		}
+	}
+	if status := game.Status(); status != engine.Won {
+		t.Fatalf("status = %v, want Won", status)
+	}
+	game.RevealAll()
+	enginetest.AssertInvariants(t, game.Board, mines)
+}
+
+func TestInvariantsWithDeferredPlacement(t *testing.T) {
+	// With first-click safety the mines are only placed on the first reveal.
+	board := models.NewMinesweeper(rows, cols)
+	game := engine.New(board, mines)
+	enginetest.AssertInvariants(t, board, mines)
+
+	game.ToggleFlag(4, 4)
+	game.ToggleFlag(4, 4)
+	game.ToggleFlag(4, 4)
+	enginetest.AssertInvariants(t, board, mines)
+
+	board.PlaceMinesAvoiding(seed, mines, 4, 4)
+	game.Reveal(4, 4)
+	enginetest.AssertInvariants(t, board, mines)
+}
+
+// TestInvariantsHoldForRandomPlay plays random sequences of actions on
+// boards of several shapes and mine densities. Each game is a subtest named
+// after its seed, and a failing game logs the steps that led to it.
+func TestInvariantsHoldForRandomPlay(t *testing.T) {
+	shapes := [][2]int{{1, 2}, {1, 9}, {9, 1}, {2, 2}, {3, 7}, {8, 8}, {16, 30}}
+	actions := []string{"reveal", "reveal", "chord", "flag", "auto-flag"}
+	games := 300
+	if testing.Short() {
+		games = 30
+	}
+	for seed := int64(1); seed <= int64(games); seed++ {
+		r := rand.New(rand.NewSource(seed))
+		shape := shapes[r.Intn(len(shapes))]
+		rows, cols := shape[0], shape[1]
+		mines := 1 + r.Intn(rows*cols-1)
+		// Half of the games place the mines on the first reveal, as with
+		// first-click safety.
+		deferred := r.Intn(2) == 0
+		name := fmt.Sprintf("seed %d, %dx%d, %d mines", seed, rows, cols, mines)
+		if deferred {
+			name += ", placed on the first reveal"
+		}
+		t.Run(name, func(t *testing.T) {
+			board := models.NewMinesweeper(rows, cols)
+			if !deferred {
+				board.PlaceMinesWithSeed(seed, mines)
+			}
+			game := engine.New(board, mines)
+			enginetest.AssertInvariants(t, board, mines)
+
+			var steps []step
+			t.Cleanup(func() {
+				if t.Failed() {
+					t.Logf("steps: %v", steps)
+				}
+			})
+			for len(steps) < 200 && game.Status() == engine.Playing {
+				// Cells just off the board are picked too, they must be ignored.
+				s := step{actions[r.Intn(len(actions))], r.Intn(rows+2) - 1, r.Intn(cols+2) - 1}
+				steps = append(steps, s)
+				if deferred && s.action == "reveal" && board.InBounds(s.row, s.col) {
+					if placed, _, _ := board.Counts(); placed == 0 {
+						board.PlaceMinesAvoiding(seed, mines, s.row, s.col)
+					}
+				}
+				s.apply(game)
+				enginetest.AssertInvariants(t, board, mines)
+			}
+			game.RevealAll()
+			enginetest.AssertInvariants(t, board, mines)
+		})
+	}
+}
+
+func TestCheckInvariantsReportsBrokenBoards(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(board *models.Minesweeper)
+	}{
+		{"shown cell without mines", func(board *models.Minesweeper) {
+			board.Board[0][0].IsShown = true
+		}},
+		{"missing mine", func(board *models.Minesweeper) {
+			board.PlaceMinesWithSeed(seed, mines-1)
+		}},
+		{"wrong nearby count", func(board *models.Minesweeper) {
+			board.PlaceMinesWithSeed(seed, mines)
+			board.Board[1][1].IsShown = true
+			board.Board[1][1].NearbyMines = 3
+		}},
+		{"ragged row", func(board *models.Minesweeper) {
+			board.PlaceMinesWithSeed(seed, mines)
+			board.Board[3] = board.Board[3][:cols-1]
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := models.NewMinesweeper(rows, cols)
+			tt.corrupt(board)
+			if err := enginetest.CheckInvariants(board, mines); err == nil {
+				t.Error("CheckInvariants accepted the broken board")
+			}
+		})
+	}
+}
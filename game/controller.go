@@ -12,8 +12,8 @@ func NewGameController(service *MinesweeperService) *GameController {
 	return &GameController{service: service}
 }
 
-func (c *GameController) StartGame(boardSize, mineQuantity int) {
-	c.service.InitGame(boardSize, mineQuantity)
+func (c *GameController) StartGame(rows, cols, mineQuantity int) {
+	c.service.InitGame(rows, cols, mineQuantity)
 }
 
 func (c *GameController) TerminateGame() {
@@ -0,0 +1,215 @@
+package game
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/dimaq12/minesweaper/engine"
+	"github.com/dimaq12/minesweaper/models"
+)
+
+// harness runs a game on a simulation screen, so tests can press keys and
+// read back what is drawn.
+type harness struct {
+	t       *testing.T
+	service *MinesweeperService
+	screen  tcell.SimulationScreen
+	exited  chan int
+	done    chan struct{}
+}
+
+// simulationScreen lets the harness know when tview has initialized the
+// screen, as SimulationScreen must not be used before that.
+type simulationScreen struct {
+	tcell.SimulationScreen
+	once  sync.Once
+	ready chan struct{}
+}
+
+func (s *simulationScreen) Init() error {
+	err := s.SimulationScreen.Init()
+	s.once.Do(func() { close(s.ready) })
+	return err
+}
+
+// startGame runs InitGame on a simulation screen and waits for the first
+// frame. Exiting the program only reports the exit code to the harness.
+func startGame(t *testing.T, service *MinesweeperService, rows, cols, mines int) *harness {
+	t.Helper()
+	h := &harness{
+		t:       t,
+		service: service,
+		screen:  tcell.NewSimulationScreen(""),
+		exited:  make(chan int, 1),
+		done:    make(chan struct{}),
+	}
+	screen := &simulationScreen{SimulationScreen: h.screen, ready: make(chan struct{})}
+	service.SetScreen(screen)
+	service.gameOverPause = 50 * time.Millisecond
+	service.exit = func(code int) {
+		h.exited <- code
+	}
+	go func() {
+		defer close(h.done)
+		service.InitGame(rows, cols, mines)
+	}()
+	select {
+	case <-screen.ready:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the screen was not initialized")
+	}
+	h.waitFor("Mines left")
+	return h
+}
+
+// press injects keys into the game. Runes are sent as themselves.
+func (h *harness) press(keys ...interface{}) {
+	for _, key := range keys {
+		switch key := key.(type) {
+		case rune:
+			h.screen.InjectKey(tcell.KeyRune, key, tcell.ModNone)
+		case tcell.Key:
+			h.screen.InjectKey(key, 0, tcell.ModNone)
+		}
+	}
+}
+
+// lines returns the text on the screen, one string per row.
+func (h *harness) lines() []string {
+	width, height := h.screen.Size()
+	lines := make([]string, height)
+	for row := range lines {
+		var line strings.Builder
+		for col := 0; col < width; col++ {
+			mainc, combc, _, _ := h.screen.GetContent(col, row)
+			if mainc == 0 {
+				mainc = ' '
+			}
+			line.WriteRune(mainc)
+			line.WriteString(string(combc))
+		}
+		lines[row] = line.String()
+	}
+	return lines
+}
+
+// waitFor waits until text shows up anywhere on the screen.
+func (h *harness) waitFor(text string) {
+	h.t.Helper()
+	h.waitUntil(text, func() bool {
+		return strings.Contains(strings.Join(h.lines(), "\n"), text)
+	})
+}
+
+// waitForRow waits until the cells of a board row read as want.
+func (h *harness) waitForRow(row int, want ...string) {
+	h.t.Helper()
+	h.waitUntil(strings.Join(want, " "), func() bool {
+		return strings.HasPrefix(strings.Join(strings.Fields(h.lines()[row]), " "), strings.Join(want, " "))
+	})
+}
+
+func (h *harness) waitUntil(what string, ok func() bool) {
+	h.t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !ok() {
+		if time.Now().After(deadline) {
+			h.t.Fatalf("timed out waiting for %q, the screen shows:\n%s", what, strings.Join(h.lines(), "\n"))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// waitForExit waits for the game to exit the program and for InitGame to
+// return, and returns the exit code.
+func (h *harness) waitForExit() int {
+	h.t.Helper()
+	select {
+	case code := <-h.exited:
+		select {
+		case <-h.done:
+		case <-time.After(5 * time.Second):
+			h.t.Fatal("InitGame did not return after the exit")
+		}
+		return code
+	case <-time.After(5 * time.Second):
+		h.t.Fatal("the game did not exit")
+		return 0
+	}
+}
+
+func TestHarnessInput(t *testing.T) {
+	h := startGame(t, NewMinesweeperService(nil), 5, 5, 3)
+	h.waitForRow(0, ".", ".", ".", ".", ".")
+
+	h.press('f')
+	h.waitForRow(0, "F", ".", ".", ".", ".")
+	h.waitFor("Mines left: 2")
+
+	h.press('f', tcell.KeyRight, 'f')
+	h.waitForRow(0, "?", "F", ".", ".", ".")
+
+	h.press(tcell.KeyDown, 'f', 'f', 'f')
+	h.waitForRow(1, ".", ".", ".", ".", ".")
+
+	h.press('q')
+	if code := h.waitForExit(); code != 0 {
+		t.Errorf("exit code = %d, want 0", code)
+	}
+}
+
+func TestHarnessQuit(t *testing.T) {
+	h := startGame(t, NewMinesweeperService(nil), 5, 5, 3)
+	h.press('q')
+	if code := h.waitForExit(); code != 0 {
+		t.Errorf("exit code = %d, want 0", code)
+	}
+	if summary := h.service.session.summary(); summary != "" {
+		t.Errorf("quitting an untouched board recorded a try: %q", summary)
+	}
+}
+
+func TestHarnessGameOver(t *testing.T) {
+	const seed, rows, cols, mines = 7, 5, 5, 3
+
+	// Without first-click safety the layout comes straight from the seed,
+	// so the position of a mine is known up front.
+	layout := models.NewMinesweeper(rows, cols)
+	layout.PlaceMinesWithSeed(seed, mines)
+	mine := position{-1, -1}
+	for row := 0; row < rows && mine.row < 0; row++ {
+		for col := 0; col < cols; col++ {
+			if layout.Board[row][col].IsMine {
+				mine = position{row, col}
+				break
+			}
+		}
+	}
+
+	service := NewMinesweeperService(nil)
+	service.SetFirstClickSafe(false)
+	service.SetSeed(seed)
+	h := startGame(t, service, rows, cols, mines)
+
+	for i := 0; i < mine.row; i++ {
+		h.press(tcell.KeyDown)
+	}
+	for i := 0; i < mine.col; i++ {
+		h.press(tcell.KeyRight)
+	}
+	h.press(tcell.KeyEnter)
+
+	if code := h.waitForExit(); code != 0 {
+		t.Errorf("exit code = %d, want 0", code)
+	}
+	if status := service.engine.Status(); status != engine.Lost {
+		t.Errorf("status = %v, want Lost", status)
+	}
+	if exploded := service.exploded.Load(); exploded == nil || *exploded != mine {
+		t.Errorf("exploded = %v, want %v", exploded, mine)
+	}
+}
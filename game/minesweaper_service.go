@@ -5,14 +5,32 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 
+	"github.com/dimaq12/minesweaper/engine"
 	"github.com/dimaq12/minesweaper/models"
+	"github.com/dimaq12/minesweaper/scores"
+	"github.com/dimaq12/minesweaper/version"
 )
 
+// frameInterval caps how often the board is redrawn (60 frames per second).
+const frameInterval = time.Second / 60
+
+// gameOverPause is how long the revealed board stays on screen once the game
+// is won or lost.
+const gameOverPause = 5 * time.Second
+
+// noGuessAttempts is how many layouts are tried before a no-guess game
+// settles for one that may need guessing.
+const noGuessAttempts = 10000
+
 type ShowTask struct {
 	Row int
 	Col int
@@ -22,46 +40,158 @@ func NewShowTask(row, col int) *ShowTask {
 	return &ShowTask{Row: row, Col: col}
 }
 
+// GameService runs a game in a frontend. The rules themselves live in the
+// engine package.
 type GameService interface {
-	InitGame(bSize int, mineQ int)
+	InitGame(rows, cols int, mineQ int)
 	EndGame()
-	showCell(row, col int)
-	flagCell(row, col int)
-	isGameOver() (bool, bool)
-	handleInput(app *tview.Application)
 }
 
 type MinesweeperService struct {
 	game            *models.Minesweeper
+	engine          *engine.Game
 	logger          io.Writer
 	renderer        *Renderer
 	app             *tview.Application
+	screen          tcell.Screen
 	mineQuantity    int
+	firstClickSafe  bool
+	noGuess         bool
+	autoFlag        atomic.Bool
+	confirmFlags    int
+	confirmWindow   time.Duration
+	lastKey         time.Time
+	pendingReveal   *ShowTask
+	opening         atomic.Pointer[Opening]
+	notice          atomic.Pointer[string]
+	exploded        atomic.Pointer[position]
+	session         session
+	minesPlaced     bool
+	timer           models.Timer
+	seed            int64
+	seedSet         bool
+	scoresPath      string
+	savePath        string
+	resume          *models.SavedGame
 	cancelFunc      context.CancelFunc
 	showTasks       chan *ShowTask
 	rerenderTasks   chan struct{}
 	checkGameStatus chan struct{}
 	revealAllBoard  chan struct{}
+	gameOverPause   time.Duration
+	exit            func(code int)
 }
 
 func NewMinesweeperService(game *models.Minesweeper) *MinesweeperService {
-	renderer := NewRenderer()
+	renderer := NewRenderer(ClassicTheme)
 	return &MinesweeperService{
-		game:     game,
-		renderer: renderer,
+		game:           game,
+		renderer:       renderer,
+		firstClickSafe: true,
+		gameOverPause:  gameOverPause,
+		exit:           os.Exit,
 	}
 }
 
-func (s *MinesweeperService) InitGame(bSize int, mineQ int) {
-	s.game = models.NewMinesweeper(bSize)
-	s.game.PlaceMinesRandomly(mineQ)
-	s.mineQuantity = mineQ
-	s.renderer.DrawBoard(s.game)
+// requestRerender asks for the board to be redrawn on the next frame. It
+// never blocks, so it is safe to call from the UI goroutine while the frame
+// loop waits for that goroutine to draw.
+func (s *MinesweeperService) requestRerender() {
+	select {
+	case s.rerenderTasks <- struct{}{}:
+	default:
+	}
+}
+
+// SetTheme sets the glyphs and colors the board is drawn with.
+func (s *MinesweeperService) SetTheme(theme Theme) {
+	s.renderer.theme = theme
+}
+
+// SetScreen makes the next InitGame run on the given screen instead of the
+// terminal, e.g. a tcell.SimulationScreen that injects keys and captures output.
+func (s *MinesweeperService) SetScreen(screen tcell.Screen) {
+	s.screen = screen
+}
+
+// SetFirstClickSafe chooses whether mines are placed only after the first
+// reveal, keeping the revealed cell and its 3x3 neighborhood safe. It is on
+// by default and takes effect on the next InitGame.
+func (s *MinesweeperService) SetFirstClickSafe(safe bool) {
+	s.firstClickSafe = safe
+}
+
+// SetNoGuess chooses whether only boards that can be cleared without guessing
+// are generated. Such boards are generated on the first reveal, so it implies
+// first-click safety. It takes effect on the next InitGame.
+func (s *MinesweeperService) SetNoGuess(noGuess bool) {
+	s.noGuess = noGuess
+}
+
+// SetRevealConfirmation makes a reveal ask for a second Enter when the cell
+// touches at least flags flagged cells and the key comes within window of the
+// previous one, guarding against fat-fingered losses. Zero flags disables it.
+func (s *MinesweeperService) SetRevealConfirmation(flags int, window time.Duration) {
+	s.confirmFlags = flags
+	s.confirmWindow = window
+}
+
+// SetScoresPath sets the file won games are recorded in. Leaving it empty
+// disables the best-times table.
+func (s *MinesweeperService) SetScoresPath(path string) {
+	s.scoresPath = path
+}
+
+// SetSeed makes the next InitGame generate its board from seed, so the same
+// seed (and, with first-click safety, the same first reveal) gives the same board.
+func (s *MinesweeperService) SetSeed(seed int64) {
+	s.seed = seed
+	s.seedSet = true
+}
+
+// SetSavePath sets the file the game is saved to with the 's' key. Leaving
+// it empty disables saving.
+func (s *MinesweeperService) SetSavePath(path string) {
+	s.savePath = path
+}
+
+// Resume makes the next InitGame continue the saved game instead of
+// starting a new board.
+func (s *MinesweeperService) Resume(saved *models.SavedGame) {
+	s.resume = saved
+}
+
+// Seed returns the seed of the current board.
+func (s *MinesweeperService) Seed() int64 {
+	return s.seed
+}
+
+// InitGame creates a board and runs the game until it ends. When a saved game
+// was passed to Resume, that game is continued and rows, cols and mineQ are
+// ignored.
+func (s *MinesweeperService) InitGame(rows, cols int, mineQ int) {
+	if s.resume != nil {
+		s.restoreGame()
+	} else {
+		s.newGame(rows, cols, mineQ)
+	}
+	fmt.Println("Seed:", s.seed)
+	s.renderer.SetSeed(s.seed)
+	s.game.Publish()
+	s.renderer.DrawBoard(s.game.Snapshot())
+	s.renderer.DrawInfoPanel(s.game.Snapshot(), s.mineQuantity, s.timer.Elapsed())
+	s.renderer.DrawStatusBar(s.game.Snapshot(), s.mineQuantity, s.timer.Elapsed())
 	s.app = tview.NewApplication()
-	s.app.SetRoot(s.renderer.boardTable, true)
+	if s.screen != nil {
+		s.app.SetScreen(s.screen)
+	}
+	s.app.SetRoot(s.renderer.layout, true)
 	s.showTasks = make(chan *ShowTask)
-	s.rerenderTasks = make(chan struct{})
-	s.checkGameStatus = make(chan struct{})
+	// A single pending request is enough, further ones coalesce into it.
+	s.rerenderTasks = make(chan struct{}, 1)
+	// A status check looks at the current board, so pending ones coalesce
+	// too, and the reveal goroutine never waits out the game-over pause.
+	s.checkGameStatus = make(chan struct{}, 1)
 	s.revealAllBoard = make(chan struct{})
 	ctx, cancel := context.WithCancel(context.TODO())
 	s.cancelFunc = cancel
@@ -74,142 +204,286 @@ func (s *MinesweeperService) InitGame(bSize int, mineQ int) {
 	}
 }
 
-func (s *MinesweeperService) EndGame() {
-	s.app.Stop()
-	s.cancelFunc()
-	os.Exit(0)
-}
-
-// ifCellValid takes a cell's row and col coordinates as input and returns
-// a boolean value indicating whether the given cell coordinates are within
-// the game board's borders. This function is used to ensure that cell
-// operations are only performed on valid cells within the game board.
-func (s *MinesweeperService) ifCellValid(row, col int) bool {
-	// Check if the given row and col are within the borders of the game board:
-	// The row must be greater than or equal to 0 and less than the total number of rows
-	// The col must be greater than or equal to 0 and less than the total number of columns
-	return row >= 0 && row < s.game.Rows && col >= 0 && col < s.game.Cols
-}
-
-// countNearbyMines  takes a cell's row and col coordinates as input and returns
-// the number of mines in the nearby cells. This function is used to calculate
-// the number of mines around a cell and is called when a cell is shown.
-func (s *MinesweeperService) countNearbyMines(row, col int) int {
-	// Initialize the nearbyMines counter to 0
-	nearbyMines := 0
-
-	// Loop through the nearby cells by using deltaRow and deltaCol (delta)
-	// deltaRow ranges from -1 to 1, representing the row above, the same row, and the row below
-	for deltaRow := -1; deltaRow <= 1; deltaRow++ {
-		// deltaCol ranges from -1 to 1, representing the column to the left, the same column, and the column to the right
-		for deltaCol := -1; deltaCol <= 1; deltaCol++ {
-			// If both deltaRow and deltaCol are 0, it means we are looking at the current cell, so skip this iteration
-			if deltaRow == 0 && deltaCol == 0 {
-				continue
-			}
+// newGame sets up a fresh board.
+func (s *MinesweeperService) newGame(rows, cols int, mineQ int) {
+	s.game = models.NewMinesweeper(rows, cols)
+	s.mineQuantity = mineQ
+	s.engine = engine.New(s.game, mineQ)
+	// With first-click safety the mines are placed on the first reveal instead,
+	// so the opening cell and its neighborhood can be kept clear.
+	if !s.seedSet {
+		s.seed = time.Now().UnixNano()
+	}
+	s.minesPlaced = false
+	if !s.firstClickSafe && !s.noGuess {
+		s.game.PlaceMinesWithSeed(s.seed, mineQ)
+		s.minesPlaced = true
+	}
+	s.timer.Reset()
+}
 
-			// Calculate the nearby cell's row and col coordinates by adding deltaRow and deltaCol to the current row and col
-			newRow, newCol := row+deltaRow, col+deltaCol
+// restoreGame continues the game passed to Resume, with the clock picking up
+// where it stopped when the game was saved.
+func (s *MinesweeperService) restoreGame() {
+	saved := s.resume
+	s.resume = nil
+	s.game = saved.Game
+	s.mineQuantity = saved.Mines
+	s.engine = engine.New(s.game, saved.Mines)
+	s.seed = saved.Seed
+	s.seedSet = true
+	s.noGuess = saved.NoGuess
+	s.minesPlaced = saved.MinesPlaced()
+	s.timer.Reset()
+	if !s.game.IsUntouched() {
+		s.timer.Restore(saved.Elapsed())
+	}
+}
 
-			// Check if the nearby cell's row and col are over the game board borders and if the cell contains a mine
-			if s.ifCellValid(newRow, newCol) && s.game.Board[newRow][newCol].IsMine {
-				// If the nearby cell contains a mine, increment the nearbyMines counter by 1
-				nearbyMines++
-			}
-		}
+// saveGame writes the game in progress to the save path and reports the
+// result in the status bar.
+func (s *MinesweeperService) saveGame() {
+	if s.savePath == "" {
+		return
+	}
+	if s.engine.Status() != engine.Playing {
+		// Keys still work during the pause after the game is over.
+		s.renderer.SetMessage("The game is over, there is nothing to save")
+		return
 	}
+	if err := s.writeSave(); err != nil {
+		s.renderer.SetMessage("Save failed: " + err.Error())
+		return
+	}
+	s.renderer.SetMessage("Saved to " + s.savePath)
+}
 
-	// Return the total number of mines found in the nearby cells
-	return nearbyMines
+// autosave saves a game in progress when the program is told to quit, so
+// it can be continued with --resume. It returns what to print about it once
+// the game has left the terminal.
+func (s *MinesweeperService) autosave() string {
+	if s.savePath == "" || s.game.IsUntouched() || s.engine.Status() != engine.Playing {
+		return ""
+	}
+	if err := s.writeSave(); err != nil {
+		return fmt.Sprintln("Could not save the game:", err)
+	}
+	return fmt.Sprintf("Game saved, continue it with: minesweeper --resume %s\n", s.savePath)
 }
 
-// showCell takes a cell's row and col coordinates as input and show
-// the cell, updating its IsShown state and the number of nearby mines.
-// If the shown cell has zero nearby mines, it recursively show
-// all neighboring cells that are not already shown.
-func (s *MinesweeperService) showCell(row, col int, recursive bool) {
-	// Check if the given row and col are within the borders of the game board,
-	// and if the cell is already shown. If either of these conditions is true,
-	// the function returns immediately without revealing the cell.
-	if !s.ifCellValid(row, col) || s.game.Board[row][col].IsShown {
-		return
+// writeSave writes the game to the save path.
+func (s *MinesweeperService) writeSave() error {
+	saved := &models.SavedGame{
+		GameVersion: version.Version,
+		Mines:       s.mineQuantity,
+		Seed:        s.seed,
+		NoGuess:     s.noGuess,
+		ElapsedMs:   s.timer.Elapsed().Milliseconds(),
+		Game:        s.game,
 	}
-	s.game.Mu.Lock()
+	return saved.Save(s.savePath)
+}
 
-	// Set the cell's IsShown property to true, indicating that it has been shown.
-	if s.game.Board[row][col].IsFlagged == !true {
-		s.game.Board[row][col].IsShown = true
+// confirmReveal reports whether the reveal at row, col may go ahead. A hurried
+// reveal next to many flags is held back until Enter is pressed again on the
+// same cell. It is called for every key, with sinceLastKey the time since the
+// previous one.
+func (s *MinesweeperService) confirmReveal(row, col int, sinceLastKey time.Duration) bool {
+	pending := s.pendingReveal
+	s.pendingReveal = nil
+	if pending != nil && pending.Row == row && pending.Col == col {
+		s.renderer.SetMessage("")
+		return true
+	}
+	if s.confirmFlags == 0 || sinceLastKey >= s.confirmWindow {
+		return true
 	}
 
-	// Update the cell's nearbyMines property with the count of nearby mines.
-	s.game.Board[row][col].NearbyMines = s.countNearbyMines(row, col)
-	s.game.Mu.Unlock()
+	snap := s.game.Snapshot()
+	if snap.Board[row][col].IsShown || snap.FlaggedNeighbors(row, col) < s.confirmFlags {
+		return true
+	}
+	s.pendingReveal = NewShowTask(row, col)
+	s.renderer.SetMessage("Press Enter again to reveal")
+	return false
+}
 
-	// If the shown cell has no nearby mines (i.e., nearbyMines is 0),
-	// recursively reveal all neighboring cells.
-	if s.game.Board[row][col].NearbyMines == 0 {
-		// Loop through all neighboring cells using relative row (deltaRow) and column (deltaCol) offsets.
-		for deltaRow := -1; deltaRow <= 1; deltaRow++ {
-			for deltaCol := -1; deltaCol <= 1; deltaCol++ {
-				// Skip the current cell 0, 0
-				if deltaRow == 0 && deltaCol == 0 {
-					continue
-				}
-				// Recursively call showCell for the neighboring cell.
-				s.showCell(row+deltaRow, col+deltaCol, true)
-			}
-		}
+// showHint highlights a hidden cell that the shown numbers prove to be safe
+// and moves the cursor onto it.
+func (s *MinesweeperService) showHint() {
+	row, col, ok := FindSafeCell(s.game.Snapshot())
+	if !ok {
+		s.renderer.ClearHint()
+		s.renderer.SetMessage("Hint: no cell is certainly safe")
+		return
 	}
-	s.rerenderTasks <- struct{}{}
-	s.checkGameStatus <- struct{}{}
+	s.renderer.SetHint(row, col)
+	s.renderer.SetMessage(fmt.Sprintf("Hint: row %d, column %d is safe", row+1, col+1))
+	s.renderer.boardTable.Select(row, col)
 }
 
-// Show all the cells on the board
-func (s *MinesweeperService) revealAll() {
-	s.game.Mu.Lock()
-	for row := 0; row < s.game.Rows; row++ {
-		for col := 0; col < s.game.Cols; col++ {
-			s.game.Board[row][col].IsShown = true
-		}
+func (s *MinesweeperService) EndGame() {
+	s.app.Stop()
+	s.cancelFunc()
+	if !s.game.IsUntouched() {
+		s.session.add(false, s.timer.Elapsed())
+	}
+	s.printSession()
+	s.exit(0)
+}
+
+// printSession prints the summary of the tries once the board was played
+// more than once.
+func (s *MinesweeperService) printSession() {
+	if summary := s.session.summary(); summary != "" {
+		fmt.Println(summary)
 	}
+}
+
+// placeMines generates the mine layout on the first reveal, keeping the
+// revealed cell and its neighborhood clear.
+func (s *MinesweeperService) placeMines(row, col int) {
+	s.minesPlaced = true
+	if !s.noGuess {
+		s.game.Mu.Lock()
+		s.game.PlaceMinesAvoiding(s.seed, s.mineQuantity, row, col)
+		s.game.Mu.Unlock()
+		return
+	}
+
+	// The search can take a while on dense boards, so it runs on a board of
+	// its own and the game stays responsive until the layout is copied in.
+	scratch := models.NewMinesweeper(s.game.Rows, s.game.Cols)
+	solvable := func(ms *models.Minesweeper) bool {
+		return IsSolvable(ms, row, col)
+	}
+	found := scratch.PlaceMinesSolvable(s.seed, s.mineQuantity, row, col, noGuessAttempts, solvable)
+	s.game.Mu.Lock()
+	s.game.CopyMines(scratch)
 	s.game.Mu.Unlock()
-	s.rerenderTasks <- struct{}{}
+	if !found {
+		s.notify("No guess-free board found, this one may need guessing")
+	}
+}
+
+// notify shows message in the status bar on the next frame. Unlike
+// Renderer.SetMessage it may be called from any goroutine.
+func (s *MinesweeperService) notify(message string) {
+	s.notice.Store(&message)
+	s.requestRerender()
 }
 
-func (s *MinesweeperService) isWinOrGameOver() (bool, bool) {
-	shownNonMineCells := 0
-	allCells := s.game.Rows * s.game.Cols
+// shownCount returns the number of shown cells.
+func (s *MinesweeperService) shownCount() int {
 	s.game.Mu.Lock()
-	for row := 0; row < s.game.Rows; row++ {
-		for col := 0; col < s.game.Cols; col++ {
-			cell := s.game.Board[row][col]
-			if cell.IsShown {
-				if cell.IsMine {
-					// If a shown cell is a mine, the player has lost.
-					s.game.Mu.Unlock()
-					return true, false
-				} else {
-					shownNonMineCells++
-				}
-			}
-		}
+	defer s.game.Mu.Unlock()
+	_, shown, _ := s.game.Counts()
+	return shown
+}
+
+// recordOpening describes the area opened by the first reveal at row, col.
+// The next frame shows it in the info panel.
+func (s *MinesweeperService) recordOpening(row, col int) {
+	s.opening.Store(&Opening{
+		Position: cellPosition(s.game.Rows, s.game.Cols, row, col),
+		Size:     s.shownCount(),
+	})
+}
+
+// recordWin adds the completion time to the best-times table and returns
+// what to print about it once the game has left the terminal. Only the first
+// try of a board counts, as a restart replays a layout the player has seen.
+func (s *MinesweeperService) recordWin(elapsed time.Duration) string {
+	if s.scoresPath == "" {
+		return ""
 	}
-	s.game.Mu.Unlock()
+	if s.session.replay() {
+		return fmt.Sprintln("Restarted boards do not count as best times.")
+	}
+	table, err := scores.Load(s.scoresPath)
+	if err != nil {
+		return fmt.Sprintln("Could not load best times:", err)
+	}
+	rank := table.Add(scores.Difficulty(s.game.Rows, s.game.Cols, s.mineQuantity, s.noGuess), elapsed, time.Now())
+	var out strings.Builder
+	if err := table.Save(s.scoresPath); err != nil {
+		fmt.Fprintln(&out, "Could not save best times:", err)
+	}
+	if rank > 0 {
+		fmt.Fprintf(&out, "New best time, rank #%d!\n", rank)
+	}
+	fmt.Fprintln(&out, table.Format())
+	return out.String()
+}
 
-	// If all non-mine cells are shown, the player has won.
-	if allCells-shownNonMineCells == s.mineQuantity {
-		return true, true
+// printOpening prints the opening of the finished game, if it is known.
+func (s *MinesweeperService) printOpening() {
+	if opening := s.opening.Load(); opening != nil {
+		fmt.Println("Opening:", opening)
 	}
+}
 
-	// The game is still ongoing.
-	return false, false
+// toggleAutoFlag switches the auto-flag assist on or off. When it is on,
+// obvious mines are flagged after every reveal.
+func (s *MinesweeperService) toggleAutoFlag() {
+	if s.autoFlag.Load() {
+		s.autoFlag.Store(false)
+		s.renderer.SetMessage("Auto-flag off")
+		return
+	}
+	s.autoFlag.Store(true)
+	s.engine.FlagObviousMines()
+	s.renderer.SetMessage("Auto-flag on")
 }
 
-// Flag Cell
-func (s *MinesweeperService) flagCell(row, col int) {
-	if s.ifCellValid(row, col) {
-		s.game.Board[row][col].IsFlagged = !s.game.Board[row][col].IsFlagged
+// restartGame starts the current board over with the same mine layout.
+// If the player has already made moves, a confirmation dialog is shown first.
+func (s *MinesweeperService) restartGame() {
+	restart := func() {
+		s.session.add(false, s.timer.Elapsed())
+		s.session.next()
+		s.game.Reset()
+		s.timer.Reset()
+		s.requestRerender()
+	}
+
+	if s.game.IsUntouched() {
+		return
 	}
+
+	modal := tview.NewModal().
+		SetText("Restart this board from the beginning?").
+		AddButtons([]string{"Restart", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			s.app.SetRoot(s.renderer.layout, true)
+			if buttonLabel == "Restart" {
+				s.renderer.ClearHint()
+				s.opening.Store(nil)
+				go restart()
+			}
+		})
+	s.app.SetRoot(modal, false)
+}
+
+// suspend hands the terminal back to the shell on Ctrl+Z. The clock is
+// paused while the game is in the background, and tview re-initializes and
+// redraws the screen once the process is continued.
+func (s *MinesweeperService) suspend() {
+	s.timer.Pause()
+	s.app.Suspend(stopProcess)
+	s.timer.Resume()
+	s.requestRerender()
+}
+
+// showAbout shows the version and build information over the board.
+func (s *MinesweeperService) showAbout() {
+	modal := tview.NewModal().
+		SetText(version.Info()).
+		AddButtons([]string{"OK"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			s.app.SetRoot(s.renderer.layout, true)
+		})
+	s.app.SetRoot(modal, false)
 }
 
 // Handle input
@@ -217,60 +491,201 @@ func (s *MinesweeperService) handleInput() {
 	s.renderer.boardTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		// Get coordinate of input
 		row, col := s.renderer.boardTable.GetSelection()
+		sinceLastKey := time.Since(s.lastKey)
+		s.lastKey = time.Now()
+
+		// Any other key cancels a reveal waiting for confirmation
+		if event.Key() != tcell.KeyEnter && s.pendingReveal != nil {
+			s.pendingReveal = nil
+			s.renderer.SetMessage("")
+		}
 
 		switch event.Key() {
 		// If enter was pressed
 		case tcell.KeyEnter:
+			if !s.confirmReveal(row, col, sinceLastKey) {
+				s.requestRerender()
+				return nil
+			}
 			s.showTasks <- NewShowTask(row, col) // Send a Show task
 
-		// If F or Q was pressed
+		// If F2 was pressed
+		case tcell.KeyF2:
+			s.restartGame()
+
+		// If Ctrl+Z was pressed
+		case tcell.KeyCtrlZ:
+			s.suspend()
+			return nil
+
+		// If Space, F, O, H, S, V, I, G, C, X, R, A or Q was pressed
 		case tcell.KeyRune:
 			switch event.Rune() {
+			case ' ':
+				// Space only chords, it never reveals a hidden cell
+				if s.game.Snapshot().Board[row][col].IsShown {
+					s.showTasks <- NewShowTask(row, col)
+				}
 			case 'f', 'F':
-				s.flagCell(row, col)
-				s.rerenderTasks <- struct{}{}
+				s.engine.ToggleFlag(row, col)
+				s.requestRerender()
+			case 'o', 'O':
+				s.toggleAutoFlag()
+				s.requestRerender()
+			case 'h', 'H':
+				s.showHint()
+				s.requestRerender()
+				// The table would also move the cursor left on 'h'
+				return nil
+			case 's', 'S':
+				s.saveGame()
+				s.requestRerender()
+			case 'v', 'V':
+				s.renderer.ToggleFrontier()
+				s.requestRerender()
+			case 'i', 'I':
+				s.renderer.ToggleInfoPanel()
+				s.requestRerender()
+			case 'g', 'G':
+				s.renderer.ToggleGrid()
+				s.requestRerender()
+				// The table would also jump to the first or last row on 'g'
+				return nil
+			case 'c', 'C':
+				s.renderer.ToggleCheckerboard()
+				s.requestRerender()
+			case 'x', 'X':
+				s.renderer.ToggleCrosshair()
+				s.requestRerender()
+			case 'r', 'R':
+				s.restartGame()
+			case 'a', 'A':
+				s.showAbout()
 			case 'q', 'Q':
 				s.EndGame()
 			}
 		}
 		return event
 	})
+
+	// Keep the crosshair following the cursor
+	s.renderer.boardTable.SetSelectionChangedFunc(func(row, col int) {
+		s.renderer.MoveCrosshair(s.game.Snapshot(), row, col)
+	})
 }
 
 // Run all listeners
 func (s *MinesweeperService) run(ctx context.Context) {
+	go func(ctx context.Context) {
+		// Shut down cleanly when the terminal goes away (e.g. a closed tmux
+		// pane or SSH session) instead of leaving the goroutines spinning.
+		// A game in progress is saved first so it is not lost.
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGHUP, syscall.SIGTERM)
+		defer signal.Stop(signals)
+		select {
+		case <-ctx.Done():
+		case <-signals:
+			saved := s.autosave()
+			s.app.Stop()
+			fmt.Print(saved)
+			s.EndGame()
+		}
+	}(ctx)
+
 	go func(ctx context.Context) {
 		for {
 			select {
 			case <-ctx.Done():
 				return
 			case task := <-s.showTasks:
-				s.showCell(task.Row, task.Col, true)
+				if !s.minesPlaced {
+					s.placeMines(task.Row, task.Col)
+				}
+				// The clock starts with the first reveal
+				s.timer.Start()
+				first := s.opening.Load() == nil && s.shownCount() == 0
+				if s.engine.IsShown(task.Row, task.Col) {
+					s.engine.Chord(task.Row, task.Col)
+				} else {
+					s.engine.Reveal(task.Row, task.Col)
+				}
+				if first {
+					s.recordOpening(task.Row, task.Col)
+				}
+				if s.autoFlag.Load() {
+					s.engine.FlagObviousMines()
+				}
+				s.requestRerender()
+				select {
+				case s.checkGameStatus <- struct{}{}:
+				default:
+				}
 			}
 		}
 	}(ctx)
 
 	go func(ctx context.Context) {
+		// Rerender requests only mark the board as dirty; the ticker draws
+		// at most once per frame, so bursts such as cascades are coalesced.
+		ticker := time.NewTicker(frameInterval)
+		defer ticker.Stop()
+		dirty := false
 		for {
 			select {
 			case <-ctx.Done():
 				return
 			case <-s.rerenderTasks:
+				dirty = true
+			case <-ticker.C:
+				if !dirty {
+					continue
+				}
+				dirty = false
+				// Copy the board once here so the UI goroutine draws
+				// without ever waiting on the game lock.
+				s.game.Publish()
 				s.app.QueueUpdateDraw(func() {
-					s.renderer.DrawBoard(s.game)
+					snap := s.game.Snapshot()
+					s.renderer.SetOpening(s.opening.Load())
+					if exploded := s.exploded.Load(); exploded != nil {
+						s.renderer.SetExploded(exploded.row, exploded.col)
+					}
+					if notice := s.notice.Swap(nil); notice != nil {
+						s.renderer.SetMessage(*notice)
+					}
+					s.renderer.DrawBoard(snap)
+					elapsed := s.timer.Elapsed()
+					s.renderer.DrawInfoPanel(snap, s.mineQuantity, elapsed)
+					s.renderer.DrawStatusBar(snap, s.mineQuantity, elapsed)
 				})
 			}
 		}
 
 	}(ctx)
 
+	go func(ctx context.Context) {
+		// Redraw every second so the elapsed time in the status bar keeps ticking.
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.requestRerender()
+			}
+		}
+	}(ctx)
+
 	go func(ctx context.Context) {
 		for {
 			select {
 			case <-ctx.Done():
 				return
 			case <-s.revealAllBoard:
-				s.revealAll()
+				s.engine.RevealAll()
+				s.requestRerender()
 			}
 		}
 
@@ -282,21 +697,35 @@ func (s *MinesweeperService) run(ctx context.Context) {
 			case <-ctx.Done():
 				return
 			case <-s.checkGameStatus:
-				gameOver, gameWon := s.isWinOrGameOver()
-
-				if gameOver {
-					if gameWon {
+				status := s.engine.Status()
+
+				if status != engine.Playing {
+					s.timer.Stop()
+					s.session.add(status == engine.Won, s.timer.Elapsed())
+					if status == engine.Won {
+						// Save the time before the pause, so quitting or
+						// closing the terminal during it cannot lose the win.
+						record := s.recordWin(s.timer.Elapsed())
 						s.revealAllBoard <- struct{}{}
-						time.Sleep(5 * time.Second)
+						time.Sleep(s.gameOverPause)
 						s.app.Stop()
-						fmt.Println("Congratulations! You won the game!")
+						fmt.Printf("Congratulations! You won the game in %s!\n", formatElapsed(s.timer.Elapsed()))
+						s.printOpening()
+						fmt.Print(record)
 					} else {
+						// Mark the mine that went off before all the others are shown
+						if row, col, ok := s.engine.Exploded(); ok {
+							s.exploded.Store(&position{row, col})
+						}
 						s.revealAllBoard <- struct{}{}
-						time.Sleep(5 * time.Second)
+						time.Sleep(s.gameOverPause)
 						s.app.Stop()
 						fmt.Println("Game Over! You hit a mine.")
+						s.printOpening()
 					}
-					os.Exit(0)
+					s.cancelFunc()
+					s.printSession()
+					s.exit(0)
 				}
 			}
 		}
@@ -0,0 +1,34 @@
+package game
+
+import "fmt"
+
+// Opening describes the area shown by the first reveal of a game.
+type Opening struct {
+	// Position is where the first revealed cell lies: "corner", "edge" or "center".
+	Position string
+	// Size is the number of cells the first reveal showed.
+	Size int
+}
+
+// String names the opening, e.g. "corner, 23 cells".
+func (o Opening) String() string {
+	if o.Size == 1 {
+		return o.Position + ", single cell"
+	}
+	return fmt.Sprintf("%s, %d cells", o.Position, o.Size)
+}
+
+// cellPosition classifies the cell at row, col as a corner, edge or center
+// cell of a rows x cols board.
+func cellPosition(rows, cols, row, col int) string {
+	onRowEdge := row == 0 || row == rows-1
+	onColEdge := col == 0 || col == cols-1
+	switch {
+	case onRowEdge && onColEdge:
+		return "corner"
+	case onRowEdge || onColEdge:
+		return "edge"
+	default:
+		return "center"
+	}
+}
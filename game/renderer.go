@@ -2,47 +2,230 @@ package game
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/dimaq12/minesweaper/models"
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
+// infoPanelWidth is the fixed width of the side panel in columns.
+const infoPanelWidth = 24
+
 type Renderer struct {
-	boardTable *tview.Table
+	theme        Theme
+	layout       *tview.Flex
+	boardRow     *tview.Flex
+	boardTable   *tview.Table
+	infoPanel    *tview.TextView
+	statusBar    *tview.TextView
+	showFrontier bool
+	showInfo     bool
+	showGrid     bool
+	checkerboard bool
+	crosshair    bool
+	cursorRow    int
+	cursorCol    int
+	seed         int64
+	message      string
+	hintSet      bool
+	hintRow      int
+	hintCol      int
+	opening      *Opening
+	exploded     bool
+	explodedRow  int
+	explodedCol  int
 }
 
-func NewRenderer() *Renderer {
-	return &Renderer{
+func NewRenderer(theme Theme) *Renderer {
+	r := &Renderer{
+		theme:      theme,
+		layout:     tview.NewFlex().SetDirection(tview.FlexRow),
+		boardRow:   tview.NewFlex(),
 		boardTable: tview.NewTable(),
+		infoPanel:  tview.NewTextView(),
+		statusBar:  tview.NewTextView(),
+	}
+	r.infoPanel.SetBorder(true).SetTitle(" Info ")
+	r.layout.AddItem(r.boardRow, 0, 1, true)
+	r.layout.AddItem(r.statusBar, 1, 0, false)
+	r.arrangeLayout()
+	return r
+}
+
+// arrangeLayout places the board and, when enabled, the info panel on its right.
+func (r *Renderer) arrangeLayout() {
+	r.boardRow.Clear()
+	r.boardRow.AddItem(r.boardTable, 0, 1, true)
+	if r.showInfo {
+		r.boardRow.AddItem(r.infoPanel, infoPanelWidth, 0, false)
 	}
 }
 
-func (r *Renderer) DrawBoard(game *models.Minesweeper) {
-	for row := 0; row < game.Rows; row++ {
-		for col := 0; col < game.Cols; col++ {
-			r.RenderCell(game, row, col)
+func (r *Renderer) DrawBoard(snap *models.Snapshot) {
+	for row := 0; row < snap.Rows; row++ {
+		for col := 0; col < snap.Cols; col++ {
+			r.RenderCell(snap, row, col)
 		}
 	}
 
 	r.boardTable.SetSelectable(true, true)
-	r.boardTable.SetFixed(game.Rows, game.Cols)
+	r.boardTable.SetFixed(snap.Rows, snap.Cols)
+	r.boardTable.SetBorders(r.showGrid)
 }
 
-func (r *Renderer) RenderCell(game *models.Minesweeper, row, col int) {
-	game.Mu.Lock()
-	defer game.Mu.Unlock()
-	cell := game.Board[row][col]
+func (r *Renderer) RenderCell(snap *models.Snapshot, row, col int) {
+	cell := snap.Board[row][col]
 
-	cellText := "."
+	cellText := r.theme.Hidden
+	color := r.theme.Text
 	if cell.IsShown {
 		if cell.IsMine {
-			cellText = "M"
+			cellText = r.theme.Mine
+			color = r.theme.MineColor
 		} else {
-			cellText = fmt.Sprintf("%d", cell.NearbyMines)
+			cellText = r.theme.Numbers[cell.NearbyMines]
+			color = r.theme.NumberColors[cell.NearbyMines]
 		}
 	} else if cell.IsFlagged {
-		cellText = "F"
+		cellText = r.theme.Flag
+		color = r.theme.FlagColor
+	} else if cell.IsQuestioned {
+		cellText = r.theme.Question
+	}
+
+	tableCell := tview.NewTableCell(cellText).SetAlign(tview.AlignCenter).SetTextColor(color)
+	if r.checkerboard && (row+col)%2 == 1 {
+		// Shade every other cell so rows and columns are easier to follow.
+		tableCell.SetBackgroundColor(r.theme.Checkerboard)
+	}
+	if r.crosshair && (row == r.cursorRow || col == r.cursorCol) {
+		// Highlight the row and column the cursor is on.
+		tableCell.SetBackgroundColor(r.theme.Crosshair)
+	}
+	if r.showFrontier {
+		// Tint the frontier and the cells no number tells anything about.
+		if snap.IsFrontier(row, col) {
+			tableCell.SetBackgroundColor(r.theme.Frontier)
+		} else if snap.IsUninformed(row, col) {
+			tableCell.SetBackgroundColor(r.theme.Uninformed)
+		}
+	}
+	if r.hintSet && row == r.hintRow && col == r.hintCol && !cell.IsShown {
+		// Mark the cell suggested by the last hint until it is revealed.
+		tableCell.SetBackgroundColor(r.theme.Hint)
+	}
+	if r.exploded && row == r.explodedRow && col == r.explodedCol {
+		// Show the mine that ended the game on the theme's alarm color.
+		tableCell.SetTextColor(tcell.ColorWhite).SetBackgroundColor(r.theme.Exploded)
+	}
+
+	r.boardTable.SetCell(row, col, tableCell)
+}
+
+// DrawInfoPanel updates the side panel with the mine and flag counters and the elapsed time.
+func (r *Renderer) DrawInfoPanel(snap *models.Snapshot, mineQuantity int, elapsed time.Duration) {
+	flags := snap.FlagCount()
+	text := fmt.Sprintf("Mines: %d\nFlags: %d\nLeft:  %d\nTime:  %s\n\nSeed:\n%d", mineQuantity, flags, mineQuantity-flags, formatElapsed(elapsed), r.seed)
+	if r.opening != nil {
+		text += "\n\nOpening:\n" + r.opening.String()
+	}
+	r.infoPanel.SetText(text)
+}
+
+// SetExploded marks the mine at row, col as the one that ended the game.
+func (r *Renderer) SetExploded(row, col int) {
+	r.exploded, r.explodedRow, r.explodedCol = true, row, col
+}
+
+// SetOpening sets the opening shown in the info panel, or hides it if opening is nil.
+func (r *Renderer) SetOpening(opening *Opening) {
+	r.opening = opening
+}
+
+// SetSeed sets the board seed shown in the info panel.
+func (r *Renderer) SetSeed(seed int64) {
+	r.seed = seed
+}
+
+// DrawStatusBar updates the status bar below the board with the number of
+// mines left to flag and the elapsed time.
+func (r *Renderer) DrawStatusBar(snap *models.Snapshot, mineQuantity int, elapsed time.Duration) {
+	text := fmt.Sprintf("Mines left: %d   Time: %s", mineQuantity-snap.FlagCount(), formatElapsed(elapsed))
+	if r.message != "" {
+		text += "   " + r.message
+	}
+	r.statusBar.SetText(text)
+}
+
+// SetMessage sets a note shown at the end of the status bar, e.g. the result
+// of saving the game. An empty message removes it.
+func (r *Renderer) SetMessage(message string) {
+	r.message = message
+}
+
+// formatElapsed formats a duration as mm:ss, or h:mm:ss past the hour.
+func formatElapsed(d time.Duration) string {
+	seconds := int(d / time.Second)
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
+
+// SetHint highlights the cell at row, col as safe to reveal.
+func (r *Renderer) SetHint(row, col int) {
+	r.hintSet, r.hintRow, r.hintCol = true, row, col
+}
+
+// ClearHint removes the hint highlight.
+func (r *Renderer) ClearHint() {
+	r.hintSet = false
+}
+
+// ToggleInfoPanel shows or hides the side panel.
+func (r *Renderer) ToggleInfoPanel() {
+	r.showInfo = !r.showInfo
+	r.arrangeLayout()
+}
+
+// MoveCrosshair records the new cursor position and, when the crosshair is
+// enabled, redraws only the rows and columns whose highlight changed.
+func (r *Renderer) MoveCrosshair(snap *models.Snapshot, row, col int) {
+	oldRow, oldCol := r.cursorRow, r.cursorCol
+	r.cursorRow, r.cursorCol = row, col
+	if !r.crosshair {
+		return
 	}
 
-	r.boardTable.SetCell(row, col, tview.NewTableCell(cellText).SetAlign(tview.AlignCenter))
+	for _, line := range []int{oldRow, row} {
+		for c := 0; c < snap.Cols; c++ {
+			r.RenderCell(snap, line, c)
+		}
+	}
+	for _, line := range []int{oldCol, col} {
+		for rr := 0; rr < snap.Rows; rr++ {
+			r.RenderCell(snap, rr, line)
+		}
+	}
+}
+
+// ToggleCrosshair switches the row and column highlight on or off.
+func (r *Renderer) ToggleCrosshair() {
+	r.crosshair = !r.crosshair
+}
+
+// ToggleGrid switches the box-drawing lines between cells on or off.
+func (r *Renderer) ToggleGrid() {
+	r.showGrid = !r.showGrid
+}
+
+// ToggleCheckerboard switches the alternating cell shading on or off.
+func (r *Renderer) ToggleCheckerboard() {
+	r.checkerboard = !r.checkerboard
+}
+
+// ToggleFrontier switches the frontier overlay on or off.
+func (r *Renderer) ToggleFrontier() {
+	r.showFrontier = !r.showFrontier
 }
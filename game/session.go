@@ -0,0 +1,72 @@
+package game
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// session sums up the tries of the board since the program started. The
+// board can only be replayed with a restart, so every try but the last one
+// counts as lost.
+type session struct {
+	mu       sync.Mutex
+	games    int
+	wins     int
+	played   time.Duration
+	best     time.Duration
+	recorded bool
+	// restarted is set once the board has been restarted, after which the
+	// player knows where some of the mines are.
+	restarted bool
+}
+
+// add records the current try as finished, restarted or quit. A try is only
+// counted once, e.g. quitting during the game-over pause does not add the
+// lost game again.
+func (s *session) add(won bool, elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.recorded {
+		return
+	}
+	s.recorded = true
+	s.games++
+	s.played += elapsed
+	if won {
+		s.wins++
+		if s.best == 0 || elapsed < s.best {
+			s.best = elapsed
+		}
+	}
+}
+
+// next starts a new try of the board, after the previous one was added.
+func (s *session) next() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recorded = false
+	s.restarted = true
+}
+
+// replay reports whether the current try is a restart of the board.
+func (s *session) replay() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.restarted
+}
+
+// summary describes the tries, or returns "" if the board was only played
+// once and there is nothing to sum up.
+func (s *session) summary() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.games < 2 {
+		return ""
+	}
+	text := fmt.Sprintf("This board: %d tries, %d won, %s played", s.games, s.wins, formatElapsed(s.played))
+	if s.wins > 0 {
+		text += ", best " + formatElapsed(s.best)
+	}
+	return text
+}
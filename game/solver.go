@@ -0,0 +1,215 @@
+package game
+
+import "github.com/dimaq12/minesweaper/models"
+
+// position is a cell coordinate on the board.
+type position struct {
+	row, col int
+}
+
+// constraint says that exactly mines of the cells are mines. Every shown
+// number yields one over its hidden neighbors.
+type constraint struct {
+	cells []position
+	mines int
+}
+
+// FindSafeCell looks for a hidden cell that the shown numbers prove to be
+// free of mines. Flags are ignored, since the player may have placed them
+// wrongly, so a flagged cell can be reported as safe. If several cells are
+// safe, the first one in row-major order is returned.
+func FindSafeCell(snap *models.Snapshot) (row, col int, ok bool) {
+	known := deduce(snap)
+	for row := 0; row < snap.Rows; row++ {
+		for col := 0; col < snap.Cols; col++ {
+			if mine, ok := known[position{row, col}]; ok && !mine {
+				return row, col, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// IsSolvable reports whether the board can be cleared without guessing when
+// the game is opened at row, col. It plays the board on a copy, revealing
+// every cell the shown numbers prove to be safe, until no such cell is left.
+// The caller is expected to hold ms.Mu.
+func IsSolvable(ms *models.Minesweeper, row, col int) bool {
+	snap := &models.Snapshot{Board: make([][]models.Cell, ms.Rows), Rows: ms.Rows, Cols: ms.Cols}
+	for r := range snap.Board {
+		snap.Board[r] = make([]models.Cell, ms.Cols)
+		for c := range snap.Board[r] {
+			snap.Board[r][c].IsMine = ms.Board[r][c].IsMine
+		}
+	}
+
+	hidden := ms.Rows * ms.Cols
+	mines, _, _ := ms.Counts()
+	if snap.Board[row][col].IsMine {
+		return false
+	}
+	hidden -= reveal(snap, row, col)
+
+	for hidden > mines {
+		progress := false
+		for cell, mine := range deduce(snap) {
+			if !mine {
+				hidden -= reveal(snap, cell.row, cell.col)
+				progress = true
+			}
+		}
+		if !progress {
+			return false
+		}
+	}
+	return true
+}
+
+// reveal shows the cell at row, col of a simulated board, opening the area
+// around it if it has no nearby mines, and returns how many cells it showed.
+func reveal(snap *models.Snapshot, row, col int) int {
+	if row < 0 || row >= snap.Rows || col < 0 || col >= snap.Cols || snap.Board[row][col].IsShown {
+		return 0
+	}
+	nearby := 0
+	for deltaRow := -1; deltaRow <= 1; deltaRow++ {
+		for deltaCol := -1; deltaCol <= 1; deltaCol++ {
+			newRow, newCol := row+deltaRow, col+deltaCol
+			if newRow >= 0 && newRow < snap.Rows && newCol >= 0 && newCol < snap.Cols && snap.Board[newRow][newCol].IsMine {
+				nearby++
+			}
+		}
+	}
+	snap.Board[row][col].IsShown = true
+	snap.Board[row][col].NearbyMines = nearby
+
+	shown := 1
+	if nearby == 0 {
+		for deltaRow := -1; deltaRow <= 1; deltaRow++ {
+			for deltaCol := -1; deltaCol <= 1; deltaCol++ {
+				shown += reveal(snap, row+deltaRow, col+deltaCol)
+			}
+		}
+	}
+	return shown
+}
+
+// deduce returns the hidden cells whose content follows from the shown
+// numbers, mapped to whether they are mines.
+func deduce(snap *models.Snapshot) map[position]bool {
+	known := map[position]bool{}
+
+	for {
+		constraints := buildConstraints(snap, known)
+		learned := false
+		learn := func(cells []position, mine bool) {
+			for _, cell := range cells {
+				if _, ok := known[cell]; !ok {
+					known[cell] = mine
+					learned = true
+				}
+			}
+		}
+
+		// A number whose unknown neighbors are all mines, or none of them.
+		for _, c := range constraints {
+			if c.mines == 0 {
+				learn(c.cells, false)
+			} else if c.mines == len(c.cells) {
+				learn(c.cells, true)
+			}
+		}
+
+		// If the cells of one number are a subset of another's, the cells
+		// only the larger one covers hold the difference of their mines.
+		byCell := map[position][]int{}
+		for i, c := range constraints {
+			for _, cell := range c.cells {
+				byCell[cell] = append(byCell[cell], i)
+			}
+		}
+		for i, a := range constraints {
+			for _, j := range byCell[a.cells[0]] {
+				b := constraints[j]
+				if i == j || len(a.cells) >= len(b.cells) || !isSubset(a.cells, b.cells) {
+					continue
+				}
+				rest := difference(b.cells, a.cells)
+				if b.mines == a.mines {
+					learn(rest, false)
+				} else if b.mines-a.mines == len(rest) {
+					learn(rest, true)
+				}
+			}
+		}
+
+		if !learned {
+			return known
+		}
+	}
+}
+
+// buildConstraints returns one constraint per shown number that still has
+// hidden neighbors not deduced yet, with the deduced mines subtracted.
+func buildConstraints(snap *models.Snapshot, known map[position]bool) []constraint {
+	var constraints []constraint
+	for row := 0; row < snap.Rows; row++ {
+		for col := 0; col < snap.Cols; col++ {
+			cell := snap.Board[row][col]
+			if !cell.IsShown || cell.IsMine {
+				continue
+			}
+			c := constraint{mines: cell.NearbyMines}
+			for deltaRow := -1; deltaRow <= 1; deltaRow++ {
+				for deltaCol := -1; deltaCol <= 1; deltaCol++ {
+					newRow, newCol := row+deltaRow, col+deltaCol
+					if newRow < 0 || newRow >= snap.Rows || newCol < 0 || newCol >= snap.Cols || snap.Board[newRow][newCol].IsShown {
+						continue
+					}
+					neighbor := position{newRow, newCol}
+					if mine, ok := known[neighbor]; ok {
+						if mine {
+							c.mines--
+						}
+						continue
+					}
+					c.cells = append(c.cells, neighbor)
+				}
+			}
+			if len(c.cells) > 0 {
+				constraints = append(constraints, c)
+			}
+		}
+	}
+	return constraints
+}
+
+// isSubset reports whether every cell of a is also in b.
+func isSubset(a, b []position) bool {
+	for _, cell := range a {
+		if !contains(b, cell) {
+			return false
+		}
+	}
+	return true
+}
+
+// difference returns the cells of b that are not in a.
+func difference(b, a []position) []position {
+	var rest []position
+	for _, cell := range b {
+		if !contains(a, cell) {
+			rest = append(rest, cell)
+		}
+	}
+	return rest
+}
+
+func contains(cells []position, cell position) bool {
+	for _, c := range cells {
+		if c == cell {
+			return true
+		}
+	}
+	return false
+}
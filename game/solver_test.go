@@ -0,0 +1,238 @@
+package game
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dimaq12/minesweaper/models"
+)
+
+// newSnapshot builds a board in play from a layout with one string per row:
+// a digit is a shown cell with that many nearby mines, '#' a hidden cell,
+// '*' a hidden mine, and 'F' and 'M' a flagged cell without and with a mine.
+func newSnapshot(t *testing.T, layout ...string) *models.Snapshot {
+	t.Helper()
+	ms := models.NewMinesweeper(len(layout), len(layout[0]))
+	for row, line := range layout {
+		for col, c := range line {
+			cell := &ms.Board[row][col]
+			switch {
+			case c == '*':
+				cell.IsMine = true
+			case c == 'M':
+				cell.IsMine, cell.IsFlagged = true, true
+			case c == 'F':
+				cell.IsFlagged = true
+			case c >= '0' && c <= '8':
+				cell.IsShown = true
+				cell.NearbyMines = int(c - '0')
+			}
+		}
+	}
+	for row, line := range layout {
+		for col := range line {
+			if cell := ms.Board[row][col]; cell.IsShown && cell.NearbyMines != ms.CountNearbyMines(row, col) {
+				t.Fatalf("layout shows %d at %d,%d, but it has %d nearby mines", cell.NearbyMines, row, col, ms.CountNearbyMines(row, col))
+			}
+		}
+	}
+	ms.Publish()
+	return ms.Snapshot()
+}
+
+// deduced draws what deduce found out: '*' for a proven mine, '.' for a
+// proven safe cell, '#' for a hidden cell still unknown and the number of
+// nearby mines for a shown cell.
+func deduced(snap *models.Snapshot, known map[position]bool) []string {
+	lines := make([]string, snap.Rows)
+	for row := range lines {
+		var line strings.Builder
+		for col, cell := range snap.Board[row] {
+			mine, ok := known[position{row, col}]
+			switch {
+			case cell.IsShown:
+				line.WriteByte(byte('0' + cell.NearbyMines))
+			case ok && mine:
+				line.WriteByte('*')
+			case ok:
+				line.WriteByte('.')
+			default:
+				line.WriteByte('#')
+			}
+		}
+		lines[row] = line.String()
+	}
+	return lines
+}
+
+func TestDeduce(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout []string
+		want   []string
+	}{
+		{
+			name:   "zero clears its neighbors",
+			layout: []string{"##*", "0##", "###"},
+			want:   []string{"..#", "0.#", "..#"},
+		},
+		{
+			name:   "as many hidden neighbors as mines",
+			layout: []string{"*1", "11"},
+			want:   []string{"*1", "11"},
+		},
+		{
+			// The 1 in the corner has its mine among two of the cells the
+			// next 1 touches, so the third one is safe.
+			name:   "1-1 at a wall",
+			layout: []string{"*##", "11#"},
+			want:   []string{"##.", "11."},
+		},
+		{
+			// Each 1 next to the 2 leaves the 2 one more mine on the far side.
+			name:   "1-2-1",
+			layout: []string{"*#*", "121", "000"},
+			want:   []string{"*.*", "121", "000"},
+		},
+		{
+			name:   "forced 50/50",
+			layout: []string{"*#", "11", "00"},
+			want:   []string{"##", "11", "00"},
+		},
+		{
+			// Taking the flag as a mine would prove the cell next to it safe.
+			name:   "flags are ignored",
+			layout: []string{"MF", "11", "00"},
+			want:   []string{"##", "11", "00"},
+		},
+		{
+			name:   "flagged safe cell proven safe",
+			layout: []string{"*#F", "110", "000"},
+			want:   []string{"*..", "110", "000"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snap := newSnapshot(t, tt.layout...)
+			if got := deduced(snap, deduce(snap)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("deduced %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindSafeCell(t *testing.T) {
+	tests := []struct {
+		name     string
+		layout   []string
+		row, col int
+		ok       bool
+	}{
+		{
+			name:   "first safe cell in row-major order",
+			layout: []string{"##*", "0##", "###"},
+			row:    0, col: 0,
+			ok: true,
+		},
+		{
+			name:   "1-2-1",
+			layout: []string{"*#*", "121", "000"},
+			row:    0, col: 1,
+			ok: true,
+		},
+		{
+			name:   "1-1 at a wall",
+			layout: []string{"*##", "11#"},
+			row:    0, col: 2,
+			ok: true,
+		},
+		{
+			name:   "forced 50/50",
+			layout: []string{"*#", "11", "00"},
+		},
+		{
+			name:   "nothing shown",
+			layout: []string{"*#", "##"},
+		},
+		{
+			name:   "flagged cell in a 50/50",
+			layout: []string{"*F", "11", "00"},
+		},
+		{
+			name:   "wrong flag proven safe",
+			layout: []string{"*#F", "110", "000"},
+			row:    0, col: 1,
+			ok: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row, col, ok := FindSafeCell(newSnapshot(t, tt.layout...))
+			if ok != tt.ok || ok && (row != tt.row || col != tt.col) {
+				t.Errorf("FindSafeCell = %d, %d, %v, want %d, %d, %v", row, col, ok, tt.row, tt.col, tt.ok)
+			}
+		})
+	}
+}
+
+func TestIsSolvable(t *testing.T) {
+	tests := []struct {
+		name     string
+		layout   []string
+		row, col int
+		want     bool
+	}{
+		{
+			name:   "flood fill opens everything",
+			layout: []string{"*..", "...", "..."},
+			row:    2, col: 2,
+			want: true,
+		},
+		{
+			name:   "1-2-1 after the opening",
+			layout: []string{"*.*", "...", "..."},
+			row:    2, col: 1,
+			want: true,
+		},
+		{
+			name:   "forced 50/50",
+			layout: []string{"*.", "..", ".."},
+			row:    2, col: 0,
+		},
+		{
+			name:   "opening on a mine",
+			layout: []string{"*.", "..", ".."},
+			row:    0, col: 0,
+		},
+		{
+			name:   "opening on a number",
+			layout: []string{"*..", "...", "..."},
+			row:    1, col: 1,
+		},
+		{
+			name:   "flags are ignored",
+			layout: []string{"*.F", "F..", "..."},
+			row:    2, col: 2,
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := models.NewMinesweeper(len(tt.layout), len(tt.layout[0]))
+			for row, line := range tt.layout {
+				for col, c := range line {
+					ms.Board[row][col].IsMine = c == '*'
+					ms.Board[row][col].IsFlagged = c == 'F'
+				}
+			}
+			if got := IsSolvable(ms, tt.row, tt.col); got != tt.want {
+				t.Errorf("IsSolvable = %v, want %v", got, tt.want)
+			}
+			// The board itself is left alone.
+			if _, shown, _ := ms.Counts(); shown != 0 {
+				t.Errorf("IsSolvable showed %d cells of the board", shown)
+			}
+		})
+	}
+}
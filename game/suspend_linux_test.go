@@ -0,0 +1,163 @@
+package game
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/dimaq12/minesweaper/models"
+)
+
+// suspendHelperEnv makes the test binary run a game on its terminal instead
+// of the tests, see TestSuspendHelper.
+const suspendHelperEnv = "MINESWEEPER_SUSPEND_HELPER"
+
+// TestSuspendHelper is not a test: it is the game that TestSuspendResume
+// starts from an interactive shell.
+func TestSuspendHelper(t *testing.T) {
+	if os.Getenv(suspendHelperEnv) != "1" {
+		t.Skip("only runs as the game of TestSuspendResume")
+	}
+	NewMinesweeperService(models.NewMinesweeper(10, 10)).InitGame(10, 10, 10)
+}
+
+// TestSuspendResume starts the game from an interactive bash on a
+// pseudo-terminal, suspends it with Ctrl+Z, brings it back with fg and
+// checks that q still quits it, i.e. that the terminal is back in raw mode.
+func TestSuspendResume(t *testing.T) {
+	if testing.Short() {
+		t.Skip("drives a real terminal")
+	}
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not found")
+	}
+	term, err := openTerminal()
+	if err != nil {
+		t.Skip("no pseudo-terminal:", err)
+	}
+	defer term.close()
+
+	cmd := exec.Command(bash, "--norc", "--noprofile", "-i")
+	cmd.Env = append(os.Environ(), "TERM=xterm", "PS1=PROMPT$ ", suspendHelperEnv+"=1")
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = term.tty, term.tty, term.tty
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Process.Kill()
+
+	term.expect(t, "PROMPT$ ")
+	term.send(fmt.Sprintf("'%s' -test.run='^TestSuspendHelper$'\n", os.Args[0]))
+	term.expect(t, "Mines left")
+	term.send("\x1a")
+	term.expect(t, "PROMPT$ ")
+	term.send("fg\n")
+	term.expect(t, "Mines left")
+	term.send("q")
+	term.expect(t, "PROMPT$ ")
+	term.send("echo EXIT=$?\n")
+	term.expect(t, "EXIT=0")
+}
+
+// terminal is the master side of a pseudo-terminal along with everything
+// read from it so far.
+type terminal struct {
+	master *os.File
+	tty    *os.File
+
+	mu     sync.Mutex
+	output bytes.Buffer
+	seen   int
+}
+
+// openTerminal opens a new pseudo-terminal and starts collecting its output.
+func openTerminal() (*terminal, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, err
+	}
+	var unlock int32
+	if err := ioctl(master, syscall.TIOCSPTLCK, unsafe.Pointer(&unlock)); err != nil {
+		master.Close()
+		return nil, err
+	}
+	var n uint32
+	if err := ioctl(master, syscall.TIOCGPTN, unsafe.Pointer(&n)); err != nil {
+		master.Close()
+		return nil, err
+	}
+	tty, err := os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return nil, err
+	}
+
+	term := &terminal{master: master, tty: tty}
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, err := master.Read(buf)
+			term.mu.Lock()
+			term.output.Write(buf[:n])
+			term.mu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return term, nil
+}
+
+func ioctl(f *os.File, request uintptr, arg unsafe.Pointer) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), request, uintptr(arg)); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func (term *terminal) send(keys string) {
+	term.master.WriteString(keys)
+}
+
+// expect waits until text shows up in the output after whatever the
+// previous expect matched.
+func (term *terminal) expect(t *testing.T, text string) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		output := term.String()
+		if i := strings.Index(output[term.seen:], text); i >= 0 {
+			term.seen += i + len(text)
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("%q did not show up, the terminal ended with:\n%s", text, tail(term.String(), 500))
+}
+
+// String returns everything the terminal has shown so far.
+func (term *terminal) String() string {
+	term.mu.Lock()
+	defer term.mu.Unlock()
+	return term.output.String()
+}
+
+func (term *terminal) close() {
+	term.tty.Close()
+	term.master.Close()
+}
+
+func tail(s string, n int) string {
+	if len(s) > n {
+		return s[len(s)-n:]
+	}
+	return s
+}
@@ -0,0 +1,7 @@
+//go:build !unix
+
+package game
+
+// stopProcess is a no-op: there is no job control to hand the terminal
+// back to, e.g. on Windows consoles.
+func stopProcess() {}
@@ -0,0 +1,22 @@
+//go:build unix
+
+package game
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// stopProcess stops the process group the way the shell's Ctrl+Z would and
+// returns once it has been continued with SIGCONT (e.g. by fg). The signal
+// is not delivered synchronously, so without waiting for SIGCONT the caller
+// could put the terminal back into raw mode before the process has actually
+// stopped, and the shell would then restore its own settings over it on fg.
+func stopProcess() {
+	continued := make(chan os.Signal, 1)
+	signal.Notify(continued, syscall.SIGCONT)
+	defer signal.Stop(continued)
+	syscall.Kill(0, syscall.SIGTSTP)
+	<-continued
+}
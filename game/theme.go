@@ -0,0 +1,149 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Theme defines the glyphs and colors the board is drawn with.
+type Theme struct {
+	Name string
+	// Glyphs of hidden, flagged, question-marked and shown mine cells.
+	Hidden   string
+	Flag     string
+	Question string
+	Mine     string
+	// Numbers holds the glyphs of shown cells with 0 to 8 nearby mines.
+	Numbers [9]string
+
+	// Text colors of hidden and question-marked cells, flags, mines and numbers.
+	Text         tcell.Color
+	FlagColor    tcell.Color
+	MineColor    tcell.Color
+	NumberColors [9]tcell.Color
+
+	// Background colors of the display layers, from the lowest to the highest.
+	Checkerboard tcell.Color
+	Crosshair    tcell.Color
+	Frontier     tcell.Color
+	Uninformed   tcell.Color
+	Hint         tcell.Color
+	Exploded     tcell.Color
+}
+
+var digits = [9]string{"0", "1", "2", "3", "4", "5", "6", "7", "8"}
+
+// ClassicTheme is the default look, with the number colors of the original game.
+var ClassicTheme = Theme{
+	Name:     "classic",
+	Hidden:   ".",
+	Flag:     "F",
+	Question: "?",
+	Mine:     "M",
+	Numbers:  digits,
+
+	Text:      tview.Styles.PrimaryTextColor,
+	FlagColor: tcell.ColorYellow,
+	MineColor: tcell.ColorRed,
+	NumberColors: [9]tcell.Color{
+		tview.Styles.PrimaryTextColor,
+		tcell.ColorBlue,
+		tcell.ColorGreen,
+		tcell.ColorRed,
+		tcell.ColorNavy,
+		tcell.ColorMaroon,
+		tcell.ColorTeal,
+		tcell.ColorSilver,
+		tcell.ColorGray,
+	},
+
+	Checkerboard: tcell.NewHexColor(0x303030),
+	Crosshair:    tcell.NewHexColor(0x202850),
+	Frontier:     tcell.ColorDarkBlue,
+	Uninformed:   tcell.ColorDarkSlateGray,
+	Hint:         tcell.ColorDarkGreen,
+	Exploded:     tcell.ColorRed,
+}
+
+// DarkTheme uses muted glyphs and lighter number colors that stay readable
+// on dark backgrounds. Empty cells are left blank.
+var DarkTheme = Theme{
+	Name:     "dark",
+	Hidden:   "·",
+	Flag:     "F",
+	Question: "?",
+	Mine:     "*",
+	Numbers:  [9]string{" ", "1", "2", "3", "4", "5", "6", "7", "8"},
+
+	Text:      tcell.ColorGray,
+	FlagColor: tcell.ColorGold,
+	MineColor: tcell.ColorOrangeRed,
+	NumberColors: [9]tcell.Color{
+		tcell.ColorGray,
+		tcell.ColorLightSkyBlue,
+		tcell.ColorLightGreen,
+		tcell.ColorSalmon,
+		tcell.ColorPlum,
+		tcell.ColorSandyBrown,
+		tcell.ColorTurquoise,
+		tcell.ColorWhiteSmoke,
+		tcell.ColorDarkGray,
+	},
+
+	Checkerboard: tcell.NewHexColor(0x1c1c1c),
+	Crosshair:    tcell.NewHexColor(0x262626),
+	Frontier:     tcell.NewHexColor(0x1a2a40),
+	Uninformed:   tcell.NewHexColor(0x2a2a2a),
+	Hint:         tcell.NewHexColor(0x1f3d1f),
+	Exploded:     tcell.ColorDarkRed,
+}
+
+// HighContrastTheme uses solid glyphs and only bright colors.
+var HighContrastTheme = Theme{
+	Name:     "high-contrast",
+	Hidden:   "#",
+	Flag:     "F",
+	Question: "?",
+	Mine:     "X",
+	Numbers:  [9]string{" ", "1", "2", "3", "4", "5", "6", "7", "8"},
+
+	Text:      tcell.ColorWhite,
+	FlagColor: tcell.ColorYellow,
+	MineColor: tcell.ColorRed,
+	NumberColors: [9]tcell.Color{
+		tcell.ColorWhite,
+		tcell.ColorAqua,
+		tcell.ColorLime,
+		tcell.ColorRed,
+		tcell.ColorFuchsia,
+		tcell.ColorYellow,
+		tcell.ColorWhite,
+		tcell.ColorWhite,
+		tcell.ColorWhite,
+	},
+
+	Checkerboard: tcell.ColorGray,
+	Crosshair:    tcell.ColorNavy,
+	Frontier:     tcell.ColorBlue,
+	Uninformed:   tcell.ColorPurple,
+	Hint:         tcell.ColorGreen,
+	Exploded:     tcell.ColorRed,
+}
+
+// Themes lists the built-in themes.
+var Themes = []Theme{ClassicTheme, DarkTheme, HighContrastTheme}
+
+// ThemeByName returns the built-in theme with the given name.
+func ThemeByName(name string) (Theme, error) {
+	var names []string
+	for _, theme := range Themes {
+		if theme.Name == name {
+			return theme, nil
+		}
+		names = append(names, theme.Name)
+	}
+	return Theme{}, fmt.Errorf("unknown theme %q (want %s)", name, strings.Join(names, ", "))
+}
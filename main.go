@@ -1,40 +1,129 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"os"
 	"strconv"
 	"strings"
 
+	"github.com/dimaq12/minesweaper/cli"
+	"github.com/dimaq12/minesweaper/config"
 	"github.com/dimaq12/minesweaper/game"
 	"github.com/dimaq12/minesweaper/models"
+	"github.com/dimaq12/minesweaper/scores"
+	"github.com/dimaq12/minesweaper/update"
+	"github.com/dimaq12/minesweaper/version"
 )
 
-func boardDimensions(level int) (boardSize, mineQuantity int) {
+func boardDimensions(level int) (rows, cols, mineQuantity int) {
 	switch level {
 	case 1:
-		return 10, 10 // 10x10 board with 10 mines
+		return 10, 10, 10 // 10x10 board with 10 mines
 	case 2:
-		return 15, 40 // 15x15 board with 40 mines
+		return 15, 15, 40 // 15x15 board with 40 mines
 	case 3:
-		return 20, 80 // 20x20 board with 80 mines
+		return 20, 20, 80 // 20x20 board with 80 mines
 	case 4:
-		return 25, 125 // 25x25 board with 125 mines
+		return 25, 25, 125 // 25x25 board with 125 mines
 	case 5:
-		return 30, 180 // 30x30 board with 180 mines
+		return 30, 30, 180 // 30x30 board with 180 mines
+	case 6:
+		return 16, 30, 99 // 16x30 board with 99 mines, the classic expert board
 	default:
-		return 10, 10 // Default to 10x10 board with 10 mines for invalid level input
+		return 10, 10, 10 // Default to 10x10 board with 10 mines for invalid level input
 	}
 }
 
-func main() {
+// resolveBoard returns the board dimensions and mine count for the level, with
+// the rows, cols and mines from cfg taking precedence.
+func resolveBoard(cfg config.Config, level int) (rows, cols, mineQuantity int, err error) {
+	rows, cols, mineQuantity = boardDimensions(level)
+	if cfg.Rows > 0 {
+		rows = cfg.Rows
+	}
+	if cfg.Cols > 0 {
+		cols = cfg.Cols
+	}
+	if cfg.Mines > 0 {
+		mineQuantity = cfg.Mines
+	}
+
+	if rows < 2 || cols < 2 {
+		return 0, 0, 0, fmt.Errorf("the board must be at least 2x2, got %dx%d", rows, cols)
+	}
+	if mineQuantity >= rows*cols {
+		return 0, 0, 0, fmt.Errorf("%d mines do not fit on a %dx%d board", mineQuantity, rows, cols)
+	}
+	return rows, cols, mineQuantity, nil
+}
+
+// runUpdate handles the "update" command, replacing the binary with the latest release.
+func runUpdate(endpoint string) error {
+	newVersion, err := update.Run(endpoint, version.Version)
+	if errors.Is(err, update.ErrUpToDate) {
+		fmt.Println("Already up to date:", version.Version)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("update failed: %w", err)
+	}
+	fmt.Println("Updated to", newVersion)
+	return nil
+}
+
+// runPlay handles the "play" command: it asks for a level unless one is
+// configured, and starts the game. A non-empty resumePath continues the game
+// saved in that file instead.
+func runPlay(cfg config.Config, resumePath string) error {
 	var input string
-	var level int
 	var err error
+	level := cfg.Level
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	theme, err := game.ThemeByName(cfg.Theme)
+	if err != nil {
+		return err
+	}
+	// A custom board needs no prompt; the level only provides the missing values.
+	if level == 0 && cfg.CustomBoard() {
+		level = 1
+	}
+
+	scoresPath, err := scores.DefaultPath()
+	if err != nil {
+		fmt.Println("Best times are disabled:", err)
+	} else if table, err := scores.Load(scoresPath); err != nil {
+		fmt.Println("Could not load best times:", err)
+	} else if len(table.Best) > 0 {
+		fmt.Println(table.Format())
+	}
+
+	if resumePath != "" {
+		return runResume(cfg, theme, resumePath, scoresPath)
+	}
+
+	savePath, saveErr := models.DefaultSavePath()
+	if saveErr == nil {
+		if _, err := os.Stat(savePath); err == nil {
+			fmt.Println("There is a saved game, continue it with: minesweeper --resume", savePath)
+		}
+	}
 
-	for {
-		fmt.Print("Enter the level (1-5) or 'q' to quit: ")
+	for level == 0 {
+		fmt.Print("Enter the level (1-6) or 'q' to quit: ")
 		_, err = fmt.Scan(&input)
 
+		if errors.Is(err, io.EOF) {
+			// Standard input is closed, nobody can answer the prompt.
+			fmt.Println()
+			fmt.Println("Quitting...")
+			return nil
+		}
 		if err != nil {
 			fmt.Println("Error reading input:", err)
 			continue
@@ -42,23 +131,141 @@ func main() {
 
 		if strings.ToLower(input) == "q" {
 			fmt.Println("Quitting...")
-			return
+			return nil
 		}
 
 		level, err = strconv.Atoi(input)
-		if err == nil && level >= 1 && level <= 5 {
+		if err == nil && level >= 1 && level <= 6 {
 			break
 		}
+		level = 0
 
-		fmt.Println("Invalid input. Please enter a level between 1 and 5 or 'q' to quit.")
+		fmt.Println("Invalid input. Please enter a level between 1 and 6 or 'q' to quit.")
 	}
 
-	fmt.Println("Level:", level)
+	rows, cols, mineQ, err := resolveBoard(cfg, level)
+	if err != nil {
+		return err
+	}
 
-	bSize, mineQ := boardDimensions(level)
+	fmt.Println("Level:", level)
 
-	minesweeperGame := models.NewMinesweeper(bSize)
+	minesweeperGame := models.NewMinesweeper(rows, cols)
 	minesweeperService := game.NewMinesweeperService(minesweeperGame)
+	minesweeperService.SetFirstClickSafe(cfg.SafeStart)
+	minesweeperService.SetNoGuess(cfg.NoGuess)
+	minesweeperService.SetRevealConfirmation(cfg.ConfirmFlags, cfg.ConfirmWindow)
+	minesweeperService.SetTheme(theme)
+	minesweeperService.SetScoresPath(scoresPath)
+	if saveErr != nil {
+		fmt.Println("Saving is disabled:", saveErr)
+	} else {
+		minesweeperService.SetSavePath(savePath)
+	}
+	if cfg.Seed != 0 {
+		minesweeperService.SetSeed(cfg.Seed)
+	}
+
+	minesweeperService.InitGame(rows, cols, mineQ)
+	return nil
+}
+
+// runResume continues the game saved at path. Saving again overwrites the same file.
+func runResume(cfg config.Config, theme game.Theme, path, scoresPath string) error {
+	saved, err := models.LoadGame(path)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Resuming:", path)
+
+	minesweeperService := game.NewMinesweeperService(saved.Game)
+	minesweeperService.SetScoresPath(scoresPath)
+	minesweeperService.SetSavePath(path)
+	minesweeperService.SetTheme(theme)
+	minesweeperService.SetRevealConfirmation(cfg.ConfirmFlags, cfg.ConfirmWindow)
+	minesweeperService.Resume(saved)
 
-	minesweeperService.InitGame(bSize, mineQ)
+	minesweeperService.InitGame(saved.Game.Rows, saved.Game.Cols, saved.Mines)
+	return nil
+}
+
+// newApp declares the commands of the binary and their flags.
+func newApp() *cli.App {
+	app := &cli.App{
+		Name:    "minesweeper",
+		Short:   "the classic mine-clearing game in your terminal",
+		Default: "play",
+	}
+
+	playFlags := flag.NewFlagSet("play", flag.ExitOnError)
+	overrides := config.NewFlags(playFlags)
+	resume := playFlags.String("resume", "", "continue the game saved in this file")
+
+	updateFlags := flag.NewFlagSet("update", flag.ExitOnError)
+	endpoint := updateFlags.String("endpoint", update.DefaultEndpoint, "URL of the latest release in the GitHub releases API format")
+
+	app.Commands = []*cli.Command{
+		{
+			Name:  "play",
+			Short: "Choose a level and play (default)",
+			Flags: playFlags,
+			Run: func(args []string) error {
+				cfg, err := config.Load()
+				if err != nil {
+					return err
+				}
+				overrides.Apply(&cfg)
+				return runPlay(cfg, *resume)
+			},
+		},
+		{
+			Name:  "version",
+			Short: "Print version and build information",
+			Run: func(args []string) error {
+				fmt.Println(version.Info())
+				return nil
+			},
+		},
+		{
+			Name:  "update",
+			Short: "Replace this binary with the latest release",
+			Flags: updateFlags,
+			Run: func(args []string) error {
+				return runUpdate(*endpoint)
+			},
+		},
+		{
+			Name:   "completion",
+			Short:  "Print a bash, zsh or fish completion script",
+			Hidden: true,
+			Run: func(args []string) error {
+				if len(args) != 1 {
+					return errors.New("usage: minesweeper completion bash|zsh|fish")
+				}
+				script, err := app.Completion(args[0])
+				if err != nil {
+					return err
+				}
+				fmt.Print(script)
+				return nil
+			},
+		},
+		{
+			Name:   "docs",
+			Short:  "Print the man page",
+			Hidden: true,
+			Run: func(args []string) error {
+				fmt.Print(app.ManPage(version.Version))
+				return nil
+			},
+		},
+	}
+	return app
+}
+
+func main() {
+	if err := newApp().Execute(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
 }
@@ -1,56 +1,123 @@
 package models
 
 import (
-	"math/rand"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type Cell struct {
-	IsMine      bool
-	IsShown     bool
-	IsFlagged   bool
-	NearbyMines int
+	IsMine       bool `json:"mine,omitempty"`
+	IsShown      bool `json:"shown,omitempty"`
+	IsFlagged    bool `json:"flagged,omitempty"`
+	IsQuestioned bool `json:"questioned,omitempty"`
+	NearbyMines  int  `json:"nearby,omitempty"`
 }
 
 type Minesweeper struct {
-	Board [][]Cell
-	Mu    sync.Mutex
-	Rows  int
-	Cols  int
+	Board    [][]Cell
+	Mu       sync.Mutex
+	Rows     int
+	Cols     int
+	snapshot atomic.Pointer[Snapshot]
 }
 
-func NewMinesweeper(boardSize int) *Minesweeper {
-	board := make([][]Cell, boardSize)
+func NewMinesweeper(rows, cols int) *Minesweeper {
+	board := make([][]Cell, rows)
 	for i := range board {
-		board[i] = make([]Cell, boardSize)
+		board[i] = make([]Cell, cols)
 	}
 
 	return &Minesweeper{
 		Board: board,
-		Rows:  boardSize,
-		Cols:  boardSize,
+		Rows:  rows,
+		Cols:  cols,
 	}
 }
 
 // PlaceMinesRandomly places N mines randomly on the game board.
 func (ms *Minesweeper) PlaceMinesRandomly(N int) {
-	// Step 1: Create a list containing the coordinates of all the cells on the board.
+	ms.PlaceMinesWithSeed(time.Now().UnixNano(), N)
+}
+
+// PlaceMinesWithSeed places N mines on the game board. A given seed always
+// produces the same layout for the same board size.
+func (ms *Minesweeper) PlaceMinesWithSeed(seed int64, N int) {
+	ms.placeMines(seed, N, func(row, col int) bool { return false })
+}
+
+// PlaceMinesAvoiding places N mines using seed while keeping the cell at
+// safeRow, safeCol and, if there is enough room, its 3x3 neighborhood free
+// of mines. It is used to make the first reveal of a game always safe.
+func (ms *Minesweeper) PlaceMinesAvoiding(seed int64, N, safeRow, safeCol int) {
+	radius := 1
+	if ms.Rows*ms.Cols-9 < N {
+		// Not enough room to clear the whole neighborhood, protect just the cell.
+		radius = 0
+	}
+	ms.placeMines(seed, N, func(row, col int) bool {
+		return abs(row-safeRow) <= radius && abs(col-safeCol) <= radius
+	})
+}
+
+// PlaceMinesSolvable places N mines like PlaceMinesAvoiding, trying new
+// layouts until solvable accepts one or maxAttempts layouts were tried. The
+// layouts are derived from seed, so the result stays reproducible. It reports
+// whether an accepted layout was found; otherwise the last one tried is kept.
+func (ms *Minesweeper) PlaceMinesSolvable(seed int64, N, safeRow, safeCol, maxAttempts int, solvable func(ms *Minesweeper) bool) bool {
+	r := NewSplitMix64(seed)
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		ms.clearMines()
+		ms.PlaceMinesAvoiding(seed, N, safeRow, safeCol)
+		if solvable(ms) {
+			return true
+		}
+		seed = int64(r.Uint64())
+	}
+	return false
+}
+
+// CopyMines places the mines of other, a board of the same size, on this
+// one, leaving the rest of the cell state alone. The caller is expected to
+// hold Mu.
+func (ms *Minesweeper) CopyMines(other *Minesweeper) {
+	for row := 0; row < ms.Rows; row++ {
+		for col := 0; col < ms.Cols; col++ {
+			ms.Board[row][col].IsMine = other.Board[row][col].IsMine
+		}
+	}
+}
+
+// clearMines removes every mine from the board.
+func (ms *Minesweeper) clearMines() {
+	for row := 0; row < ms.Rows; row++ {
+		for col := 0; col < ms.Cols; col++ {
+			ms.Board[row][col].IsMine = false
+		}
+	}
+}
+
+// placeMines places N mines randomly on the cells for which excluded returns false.
+func (ms *Minesweeper) placeMines(seed int64, N int, excluded func(row, col int) bool) {
+	// Step 1: Create a list containing the coordinates of all the candidate cells on the board.
 	// Create a slice of [2]int, where each element represents a cell's coordinates.
-	coords := make([][2]int, ms.Rows*ms.Cols)
+	coords := make([][2]int, 0, ms.Rows*ms.Cols)
 	// Iterate through each row of the game board.
 	for row := 0; row < ms.Rows; row++ {
 		// Iterate through each column of the game board.
 		for col := 0; col < ms.Cols; col++ {
-			// Store the current row and column in the 'coords' slice.
-			coords[row*ms.Cols+col] = [2]int{row, col}
+			// Store the current row and column in the 'coords' slice unless the cell must stay safe.
+			if !excluded(row, col) {
+				coords = append(coords, [2]int{row, col})
+			}
 		}
 	}
 
 	// Step 2: Shuffle the list using the Fisher-Yates shuffle algorithm.
 	// https://en.wikipedia.org/wiki/Fisher–Yates_shuffle
-	// Seed the random number generator using the current Unix timestamp.
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	// Seed the random number generator.
+	// SplitMix64 is used so a given seed produces the same layout everywhere.
+	r := NewSplitMix64(seed)
 	// Iterate through the 'coords' slice in reverse.
 	for i := len(coords) - 1; i > 0; i-- {
 		// Generate a random index 'j' within the range [0, i].
@@ -68,3 +135,97 @@ func (ms *Minesweeper) PlaceMinesRandomly(N int) {
 		ms.Board[row][col].IsMine = true
 	}
 }
+
+// Reset hides every cell and removes all flags while keeping the mine
+// layout, so the same board can be played again from the start.
+func (ms *Minesweeper) Reset() {
+	ms.Mu.Lock()
+	defer ms.Mu.Unlock()
+	for row := 0; row < ms.Rows; row++ {
+		for col := 0; col < ms.Cols; col++ {
+			ms.Board[row][col].IsShown = false
+			ms.Board[row][col].IsFlagged = false
+			ms.Board[row][col].IsQuestioned = false
+			ms.Board[row][col].NearbyMines = 0
+		}
+	}
+}
+
+// IsUntouched reports whether no cell has been shown or marked yet.
+func (ms *Minesweeper) IsUntouched() bool {
+	ms.Mu.Lock()
+	defer ms.Mu.Unlock()
+	for row := 0; row < ms.Rows; row++ {
+		for col := 0; col < ms.Cols; col++ {
+			cell := ms.Board[row][col]
+			if cell.IsShown || cell.IsFlagged || cell.IsQuestioned {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// InBounds reports whether the given row and col lie on the board.
+func (ms *Minesweeper) InBounds(row, col int) bool {
+	return row >= 0 && row < ms.Rows && col >= 0 && col < ms.Cols
+}
+
+// CountNearbyMines takes a cell's row and col coordinates as input and returns
+// the number of mines in the nearby cells. This function is used to calculate
+// the number of mines around a cell and is called when a cell is shown.
+// The caller is expected to hold Mu.
+func (ms *Minesweeper) CountNearbyMines(row, col int) int {
+	// Initialize the nearbyMines counter to 0
+	nearbyMines := 0
+
+	// Loop through the nearby cells by using deltaRow and deltaCol (delta)
+	// deltaRow ranges from -1 to 1, representing the row above, the same row, and the row below
+	for deltaRow := -1; deltaRow <= 1; deltaRow++ {
+		// deltaCol ranges from -1 to 1, representing the column to the left, the same column, and the column to the right
+		for deltaCol := -1; deltaCol <= 1; deltaCol++ {
+			// If both deltaRow and deltaCol are 0, it means we are looking at the current cell, so skip this iteration
+			if deltaRow == 0 && deltaCol == 0 {
+				continue
+			}
+
+			// Calculate the nearby cell's row and col coordinates by adding deltaRow and deltaCol to the current row and col
+			newRow, newCol := row+deltaRow, col+deltaCol
+
+			// Check if the nearby cell's row and col are over the game board borders and if the cell contains a mine
+			if ms.InBounds(newRow, newCol) && ms.Board[newRow][newCol].IsMine {
+				// If the nearby cell contains a mine, increment the nearbyMines counter by 1
+				nearbyMines++
+			}
+		}
+	}
+
+	// Return the total number of mines found in the nearby cells
+	return nearbyMines
+}
+
+// Counts returns the number of mines, shown cells and flagged hidden cells
+// on the board. The caller is expected to hold Mu.
+func (ms *Minesweeper) Counts() (mines, shown, flagged int) {
+	for row := 0; row < ms.Rows; row++ {
+		for col := 0; col < ms.Cols; col++ {
+			cell := ms.Board[row][col]
+			if cell.IsMine {
+				mines++
+			}
+			if cell.IsShown {
+				shown++
+			} else if cell.IsFlagged {
+				flagged++
+			}
+		}
+	}
+	return mines, shown, flagged
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+// mineLayout draws the mines of ms as one string per row, '*' for a mine.
+func mineLayout(ms *Minesweeper) []string {
+	layout := make([]string, ms.Rows)
+	for row := range layout {
+		line := make([]byte, ms.Cols)
+		for col := range line {
+			line[col] = '.'
+			if ms.Board[row][col].IsMine {
+				line[col] = '*'
+			}
+		}
+		layout[row] = string(line)
+	}
+	return layout
+}
+
+// The layouts below are golden: a seed has to keep producing the same board,
+// or shared seeds and saved games would no longer replay the same game.
+func TestPlaceMinesWithSeed(t *testing.T) {
+	tests := []struct {
+		rows, cols int
+		seed       int64
+		mines      int
+		want       []string
+	}{
+		{
+			rows: 8, cols: 8, seed: 42, mines: 10,
+			want: []string{
+				"..*.....",
+				"........",
+				"*......*",
+				"...*....",
+				"........",
+				"..**.*..",
+				".......*",
+				"..*...*.",
+			},
+		},
+		{
+			rows: 5, cols: 10, seed: -7, mines: 12,
+			want: []string{
+				".*..*.....",
+				"*..**.....",
+				"..**......",
+				"*....*....",
+				"..*...*..*",
+			},
+		},
+	}
+	for _, tt := range tests {
+		ms := NewMinesweeper(tt.rows, tt.cols)
+		ms.PlaceMinesWithSeed(tt.seed, tt.mines)
+		if got := mineLayout(ms); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("seed %d: layout\n%v\nwant\n%v", tt.seed, got, tt.want)
+		}
+	}
+}
+
+func TestPlaceMinesAvoiding(t *testing.T) {
+	tests := []struct {
+		rows, cols       int
+		seed             int64
+		mines            int
+		safeRow, safeCol int
+		want             []string
+	}{
+		{
+			rows: 8, cols: 8, seed: 42, mines: 10, safeRow: 3, safeCol: 3,
+			want: []string{
+				"......*.",
+				".*......",
+				".....*..",
+				"........",
+				".....*.*",
+				"......**",
+				".*......",
+				"*.*.....",
+			},
+		},
+		{
+			rows: 8, cols: 8, seed: 42, mines: 10, safeRow: 0, safeCol: 7,
+			want: []string{
+				"..*.....",
+				"........",
+				"*..**...",
+				"..*.*..*",
+				"...*....",
+				".*..*...",
+				"........",
+				"........",
+			},
+		},
+		{
+			// Too crowded to clear the neighborhood, only the cell stays safe.
+			rows: 3, cols: 3, seed: 1, mines: 5, safeRow: 1, safeCol: 1,
+			want: []string{
+				"..*",
+				"*.*",
+				"*.*",
+			},
+		},
+	}
+	for _, tt := range tests {
+		ms := NewMinesweeper(tt.rows, tt.cols)
+		ms.PlaceMinesAvoiding(tt.seed, tt.mines, tt.safeRow, tt.safeCol)
+		if got := mineLayout(ms); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("seed %d, safe %d,%d: layout\n%v\nwant\n%v", tt.seed, tt.safeRow, tt.safeCol, got, tt.want)
+		}
+	}
+}
+
+func TestCopyMines(t *testing.T) {
+	from := NewMinesweeper(3, 4)
+	from.PlaceMinesWithSeed(42, 4)
+	ms := NewMinesweeper(3, 4)
+	ms.PlaceMinesWithSeed(7, 4)
+	ms.Board[0][0].IsFlagged = true
+	ms.Board[2][3].IsQuestioned = true
+
+	ms.CopyMines(from)
+	if got, want := mineLayout(ms), mineLayout(from); !reflect.DeepEqual(got, want) {
+		t.Errorf("layout\n%v\nwant\n%v", got, want)
+	}
+	if !ms.Board[0][0].IsFlagged || !ms.Board[2][3].IsQuestioned {
+		t.Error("CopyMines cleared the marks on the board")
+	}
+}
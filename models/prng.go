@@ -0,0 +1,40 @@
+package models
+
+// SplitMix64 is a small pseudo-random number generator with a fully specified
+// algorithm, so the same seed yields the same sequence on every platform and
+// Go version. It is used for mine placement instead of math/rand, whose
+// output is not guaranteed to stay stable between releases.
+// http://xoshiro.di.unimi.it/splitmix64.c
+type SplitMix64 struct {
+	state uint64
+}
+
+func NewSplitMix64(seed int64) *SplitMix64 {
+	return &SplitMix64{state: uint64(seed)}
+}
+
+// Uint64 returns the next 64-bit value of the sequence.
+func (r *SplitMix64) Uint64() uint64 {
+	r.state += 0x9e3779b97f4a7c15
+	z := r.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Intn returns a value in the range [0, n). It uses rejection sampling so
+// every value is equally likely. It panics if n <= 0.
+func (r *SplitMix64) Intn(n int) int {
+	if n <= 0 {
+		panic("models: invalid argument to Intn")
+	}
+	bound := uint64(n)
+	// Reject values from the incomplete last bucket to avoid modulo bias.
+	limit := ^uint64(0) - (^uint64(0)%bound+1)%bound
+	for {
+		v := r.Uint64()
+		if v <= limit {
+			return int(v % bound)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import "testing"
+
+// The expected values are the reference outputs of splitmix64.c.
+func TestSplitMix64Uint64(t *testing.T) {
+	tests := []struct {
+		seed int64
+		want []uint64
+	}{
+		{0, []uint64{0xe220a8397b1dcdaf, 0x6e789e6aa1b965f4, 0x06c45d188009454f, 0xf88bb8a8724c81ec}},
+		{1234567, []uint64{6457827717110365317, 3203168211198807973, 9817491932198370423, 4593380528125082431, 16408922859458223821}},
+	}
+	for _, tt := range tests {
+		r := NewSplitMix64(tt.seed)
+		for i, want := range tt.want {
+			if got := r.Uint64(); got != want {
+				t.Errorf("seed %d: value %d = %#x, want %#x", tt.seed, i, got, want)
+			}
+		}
+	}
+}
+
+func TestSplitMix64Intn(t *testing.T) {
+	r := NewSplitMix64(1)
+	want := []int{5, 9, 0, 5, 1, 8, 5, 3}
+	for i, w := range want {
+		if got := r.Intn(10); got != w {
+			t.Errorf("value %d = %d, want %d", i, got, w)
+		}
+	}
+
+	r = NewSplitMix64(99)
+	for i := 0; i < 1000; i++ {
+		if got := r.Intn(7); got < 0 || got >= 7 {
+			t.Fatalf("Intn(7) = %d, out of range", got)
+		}
+	}
+}
+
+func TestSplitMix64IntnPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Intn(0) did not panic")
+		}
+	}()
+	NewSplitMix64(1).Intn(0)
+}
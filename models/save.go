@@ -0,0 +1,156 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SaveFormat is the version of the save file layout written by SavedGame.Save.
+const SaveFormat = 1
+
+// board is the serialized form of a Minesweeper.
+type board struct {
+	Rows  int      `json:"rows"`
+	Cols  int      `json:"cols"`
+	Board [][]Cell `json:"board"`
+}
+
+// MarshalJSON encodes the board size and every cell, holding Mu while
+// reading the board.
+func (ms *Minesweeper) MarshalJSON() ([]byte, error) {
+	ms.Mu.Lock()
+	defer ms.Mu.Unlock()
+	return json.Marshal(board{Rows: ms.Rows, Cols: ms.Cols, Board: ms.Board})
+}
+
+// UnmarshalJSON restores a board written by MarshalJSON. The number of
+// nearby mines of a shown cell is counted again rather than read from data,
+// so an edited file cannot show a count the board does not have. The next
+// call to Snapshot publishes the restored board.
+func (ms *Minesweeper) UnmarshalJSON(data []byte) error {
+	var b board
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	if b.Rows < 1 || b.Cols < 1 || len(b.Board) != b.Rows {
+		return fmt.Errorf("invalid board: %d rows of %d cells", len(b.Board), b.Cols)
+	}
+	for _, row := range b.Board {
+		if len(row) != b.Cols {
+			return fmt.Errorf("invalid board: row of %d cells, want %d", len(row), b.Cols)
+		}
+	}
+
+	ms.Mu.Lock()
+	ms.Rows, ms.Cols, ms.Board = b.Rows, b.Cols, b.Board
+	for row := range ms.Board {
+		for col := range ms.Board[row] {
+			cell := &ms.Board[row][col]
+			cell.NearbyMines = 0
+			if cell.IsShown {
+				cell.NearbyMines = ms.CountNearbyMines(row, col)
+			}
+		}
+	}
+	ms.Mu.Unlock()
+	ms.snapshot.Store(nil)
+	return nil
+}
+
+// SavedGame is a game in progress as stored in a save file.
+type SavedGame struct {
+	Format int `json:"format"`
+	// GameVersion is the version of the binary that wrote the file.
+	GameVersion string `json:"game_version"`
+	Mines       int    `json:"mines"`
+	Seed        int64  `json:"seed"`
+	// NoGuess is set if the mines still to be placed must give a board
+	// that can be cleared without guessing.
+	NoGuess   bool  `json:"no_guess,omitempty"`
+	ElapsedMs int64 `json:"elapsed_ms"`
+	// Game holds no mines if it was saved before the first reveal with
+	// first-click safety on; the layout is then generated from Seed later.
+	Game *Minesweeper `json:"game"`
+}
+
+// MinesPlaced reports whether the mine layout of the saved game has been generated.
+func (g *SavedGame) MinesPlaced() bool {
+	mines, _, _ := g.Game.Counts()
+	return mines > 0
+}
+
+// Elapsed returns the time played before the game was saved.
+func (g *SavedGame) Elapsed() time.Duration {
+	return time.Duration(g.ElapsedMs) * time.Millisecond
+}
+
+// DefaultSavePath returns ~/.minesweeper/save.json.
+func DefaultSavePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".minesweeper", "save.json"), nil
+}
+
+// LoadGame reads the saved game at path.
+func LoadGame(path string) (*SavedGame, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	saved := &SavedGame{}
+	if err := json.Unmarshal(data, saved); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if saved.Format != SaveFormat {
+		return nil, fmt.Errorf("reading %s: unsupported save format %d", path, saved.Format)
+	}
+	if saved.Game == nil {
+		return nil, fmt.Errorf("reading %s: no board", path)
+	}
+	if saved.Mines < 1 || saved.Mines >= saved.Game.Rows*saved.Game.Cols {
+		return nil, fmt.Errorf("reading %s: %d mines do not fit on a %dx%d board", path, saved.Mines, saved.Game.Rows, saved.Game.Cols)
+	}
+	mines, shown, _ := saved.Game.Counts()
+	if mines > 0 && mines != saved.Mines {
+		return nil, fmt.Errorf("reading %s: board has %d mines, want %d", path, mines, saved.Mines)
+	}
+	if mines == 0 && shown > 0 {
+		return nil, fmt.Errorf("reading %s: board shows cells but has no mines", path)
+	}
+	// A game is only saved while it is being played, which ends once a mine
+	// is shown.
+	for _, row := range saved.Game.Board {
+		for _, cell := range row {
+			if cell.IsShown && cell.IsMine {
+				return nil, fmt.Errorf("reading %s: board shows a mine", path)
+			}
+		}
+	}
+	if saved.ElapsedMs < 0 {
+		return nil, fmt.Errorf("reading %s: negative elapsed time", path)
+	}
+	return saved, nil
+}
+
+// Save writes the game to path, creating its directory if needed.
+func (g *SavedGame) Save(path string) error {
+	g.Format = SaveFormat
+	data, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// Write to a temporary file first so a crash never leaves a truncated save.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
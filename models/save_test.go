@@ -0,0 +1,140 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadGame(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name: "game in progress",
+			data: `{"format":1,"mines":1,"elapsed_ms":1500,"game":{"rows":2,"cols":2,"board":[
+				[{"mine":true},{"shown":true,"nearby":1}],
+				[{"flagged":true},{}]]}}`,
+		},
+		{
+			// Saved before the first reveal with first-click safety on.
+			name: "mines not placed yet",
+			data: `{"format":1,"mines":1,"game":{"rows":2,"cols":2,"board":[[{},{}],[{},{}]]}}`,
+		},
+		{
+			name:    "not json",
+			data:    `{"format":1,`,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported format",
+			data:    `{"format":2,"mines":1,"game":{"rows":1,"cols":2,"board":[[{"mine":true},{}]]}}`,
+			wantErr: true,
+		},
+		{
+			name:    "no board",
+			data:    `{"format":1,"mines":1}`,
+			wantErr: true,
+		},
+		{
+			name:    "ragged board",
+			data:    `{"format":1,"mines":1,"game":{"rows":2,"cols":2,"board":[[{"mine":true},{}],[{}]]}}`,
+			wantErr: true,
+		},
+		{
+			name:    "too many mines",
+			data:    `{"format":1,"mines":2,"game":{"rows":1,"cols":2,"board":[[{"mine":true},{"mine":true}]]}}`,
+			wantErr: true,
+		},
+		{
+			name:    "mine count differs",
+			data:    `{"format":1,"mines":2,"game":{"rows":2,"cols":2,"board":[[{"mine":true},{}],[{},{}]]}}`,
+			wantErr: true,
+		},
+		{
+			name:    "shown cells without mines",
+			data:    `{"format":1,"mines":1,"game":{"rows":1,"cols":2,"board":[[{"shown":true},{}]]}}`,
+			wantErr: true,
+		},
+		{
+			name:    "shown mine",
+			data:    `{"format":1,"mines":1,"game":{"rows":1,"cols":2,"board":[[{"mine":true,"shown":true},{}]]}}`,
+			wantErr: true,
+		},
+		{
+			name:    "negative elapsed time",
+			data:    `{"format":1,"mines":1,"elapsed_ms":-1,"game":{"rows":1,"cols":2,"board":[[{"mine":true},{}]]}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "save.json")
+			if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadGame(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadGame error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadGameCountsNearbyMines(t *testing.T) {
+	// The counts in the file are wrong, one of them beyond any a cell can have.
+	data := `{"format":1,"mines":2,"game":{"rows":2,"cols":3,"board":[
+		[{"mine":true},{"shown":true,"nearby":9},{"shown":true,"nearby":-1}],
+		[{"mine":true},{"shown":true},{"nearby":5}]]}}`
+	path := filepath.Join(t.TempDir(), "save.json")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := LoadGame(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]int{{0, 2, 0}, {0, 2, 0}}
+	for row := range want {
+		for col, nearby := range want[row] {
+			if got := saved.Game.Board[row][col].NearbyMines; got != nearby {
+				t.Errorf("cell %d,%d: %d nearby mines, want %d", row, col, got, nearby)
+			}
+		}
+	}
+}
+
+func TestSaveThenLoad(t *testing.T) {
+	game := NewMinesweeper(3, 3)
+	game.PlaceMinesWithSeed(1, 2)
+	for row := range game.Board {
+		for col := range game.Board[row] {
+			if !game.Board[row][col].IsMine {
+				game.Board[row][col].IsShown = true
+				game.Board[row][col].NearbyMines = game.CountNearbyMines(row, col)
+				break
+			}
+		}
+	}
+	path := filepath.Join(t.TempDir(), "dir", "save.json")
+	saved := &SavedGame{Mines: 2, Seed: 1, ElapsedMs: 42, Game: game}
+	if err := saved.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadGame(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Seed != 1 || loaded.Elapsed() != saved.Elapsed() {
+		t.Errorf("loaded seed %d after %v, want seed 1 after %v", loaded.Seed, loaded.Elapsed(), saved.Elapsed())
+	}
+	for row := range game.Board {
+		for col, cell := range game.Board[row] {
+			if got := loaded.Game.Board[row][col]; got != cell {
+				t.Errorf("cell %d,%d = %+v, want %+v", row, col, got, cell)
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package models
+
+// Snapshot is an immutable copy of the board. The renderer draws from the
+// latest published snapshot instead of locking the live board, so drawing
+// never contends with reveals and flags applied by the input goroutines.
+type Snapshot struct {
+	Board [][]Cell
+	Rows  int
+	Cols  int
+}
+
+// Publish copies the current board into a new snapshot and makes it
+// available to readers atomically.
+func (ms *Minesweeper) Publish() {
+	ms.Mu.Lock()
+	board := make([][]Cell, ms.Rows)
+	for row := range board {
+		board[row] = make([]Cell, ms.Cols)
+		copy(board[row], ms.Board[row])
+	}
+	ms.Mu.Unlock()
+
+	ms.snapshot.Store(&Snapshot{Board: board, Rows: ms.Rows, Cols: ms.Cols})
+}
+
+// Snapshot returns the most recently published snapshot, publishing one
+// first if none exists yet. The returned value must not be modified.
+func (ms *Minesweeper) Snapshot() *Snapshot {
+	if snap := ms.snapshot.Load(); snap != nil {
+		return snap
+	}
+	ms.Publish()
+	return ms.snapshot.Load()
+}
+
+// IsFrontier reports whether the cell at row, col is still hidden but touches
+// at least one shown cell, i.e. it is constrained by a visible number.
+func (s *Snapshot) IsFrontier(row, col int) bool {
+	if s.Board[row][col].IsShown {
+		return false
+	}
+	for deltaRow := -1; deltaRow <= 1; deltaRow++ {
+		for deltaCol := -1; deltaCol <= 1; deltaCol++ {
+			if deltaRow == 0 && deltaCol == 0 {
+				continue
+			}
+			newRow, newCol := row+deltaRow, col+deltaCol
+			if newRow >= 0 && newRow < s.Rows && newCol >= 0 && newCol < s.Cols && s.Board[newRow][newCol].IsShown {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsUninformed reports whether the cell at row, col is hidden and no shown
+// number says anything about it.
+func (s *Snapshot) IsUninformed(row, col int) bool {
+	return !s.Board[row][col].IsShown && !s.IsFrontier(row, col)
+}
+
+// FlagCount returns the number of flags placed on hidden cells.
+func (s *Snapshot) FlagCount() int {
+	flags := 0
+	for row := 0; row < s.Rows; row++ {
+		for col := 0; col < s.Cols; col++ {
+			if s.Board[row][col].IsFlagged && !s.Board[row][col].IsShown {
+				flags++
+			}
+		}
+	}
+	return flags
+}
+
+// FlaggedNeighbors returns the number of flagged hidden cells around row, col.
+func (s *Snapshot) FlaggedNeighbors(row, col int) int {
+	flags := 0
+	for deltaRow := -1; deltaRow <= 1; deltaRow++ {
+		for deltaCol := -1; deltaCol <= 1; deltaCol++ {
+			if deltaRow == 0 && deltaCol == 0 {
+				continue
+			}
+			newRow, newCol := row+deltaRow, col+deltaCol
+			if newRow >= 0 && newRow < s.Rows && newCol >= 0 && newCol < s.Cols {
+				if cell := s.Board[newRow][newCol]; cell.IsFlagged && !cell.IsShown {
+					flags++
+				}
+			}
+		}
+	}
+	return flags
+}
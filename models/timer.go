@@ -0,0 +1,84 @@
+package models
+
+import (
+	"sync"
+	"time"
+)
+
+// Timer measures the elapsed time of a game. It starts on the first call to
+// Start and freezes on Stop. The zero value is ready to use.
+type Timer struct {
+	mu      sync.Mutex
+	started time.Time
+	stopped time.Time
+	paused  time.Time
+}
+
+// Start starts the timer unless it is already running or stopped.
+func (t *Timer) Start() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.started.IsZero() {
+		t.started = time.Now()
+	}
+}
+
+// Stop freezes the elapsed time.
+func (t *Timer) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.started.IsZero() && t.stopped.IsZero() {
+		t.stopped = time.Now()
+	}
+}
+
+// Pause stops the clock until Resume, e.g. while the game is suspended.
+func (t *Timer) Pause() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.started.IsZero() && t.stopped.IsZero() && t.paused.IsZero() {
+		t.paused = time.Now()
+	}
+}
+
+// Resume continues a paused clock, leaving the paused time out of Elapsed.
+func (t *Timer) Resume() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.paused.IsZero() {
+		t.started = t.started.Add(time.Since(t.paused))
+		t.paused = time.Time{}
+	}
+}
+
+// Reset clears the timer so the next Start begins from zero.
+func (t *Timer) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.started, t.stopped, t.paused = time.Time{}, time.Time{}, time.Time{}
+}
+
+// Restore makes the timer run as if it had been started elapsed ago, e.g.
+// when a saved game is resumed.
+func (t *Timer) Restore(elapsed time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.started, t.stopped, t.paused = time.Now().Add(-elapsed), time.Time{}, time.Time{}
+}
+
+// Elapsed returns the time between Start and Stop, or until now if the timer
+// is still running. It is zero before Start.
+func (t *Timer) Elapsed() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch {
+	case t.started.IsZero():
+		return 0
+	case !t.paused.IsZero():
+		return t.paused.Sub(t.started)
+	case t.stopped.IsZero():
+		return time.Since(t.started)
+	default:
+		return t.stopped.Sub(t.started)
+	}
+}
@@ -0,0 +1,164 @@
+// Package scores keeps the best completion times per difficulty in a local
+// JSON file, by default ~/.minesweeper/scores.json.
+package scores
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxEntries is the number of best times kept per difficulty.
+const MaxEntries = 5
+
+type Entry struct {
+	Millis int64     `json:"millis"`
+	Date   time.Time `json:"date"`
+}
+
+// Duration returns the completion time of the entry.
+func (e Entry) Duration() time.Duration {
+	return time.Duration(e.Millis) * time.Millisecond
+}
+
+// same reports whether two entries record the same result.
+func (e Entry) same(other Entry) bool {
+	return e.Millis == other.Millis && e.Date.Equal(other.Date)
+}
+
+// Table maps a difficulty label to its best entries, fastest first.
+type Table struct {
+	Best map[string][]Entry `json:"best"`
+}
+
+// Difficulty returns the label under which results for a board are stored.
+// Boards generated to be solvable without guessing are ranked on their own.
+func Difficulty(rows, cols, mines int, noGuess bool) string {
+	label := fmt.Sprintf("%dx%d, %d mines", rows, cols, mines)
+	if noGuess {
+		label += ", no guess"
+	}
+	return label
+}
+
+// DefaultPath returns ~/.minesweeper/scores.json.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".minesweeper", "scores.json"), nil
+}
+
+// Load reads the table at path. A missing file yields an empty table.
+func Load(path string) (*Table, error) {
+	table := &Table{Best: map[string][]Entry{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return table, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, table); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if table.Best == nil {
+		table.Best = map[string][]Entry{}
+	}
+	return table, nil
+}
+
+// Save merges the table with what is currently stored at path, so results
+// written by another game in the meantime are kept, and writes it back.
+func (t *Table) Save(path string) error {
+	stored, err := Load(path)
+	if err != nil {
+		return err
+	}
+	stored.Merge(t)
+	*t = *stored
+
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// Write to a temporary file first so a crash never leaves a truncated table.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Add records a completion time and returns its 1-based rank for the
+// difficulty, or 0 if it did not make the table.
+func (t *Table) Add(difficulty string, elapsed time.Duration, date time.Time) int {
+	entry := Entry{Millis: elapsed.Milliseconds(), Date: date.Round(0)}
+	t.Best[difficulty] = insert(t.Best[difficulty], entry)
+	for i, e := range t.Best[difficulty] {
+		if e.same(entry) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Merge adds all entries of other to the table, dropping duplicates.
+func (t *Table) Merge(other *Table) {
+	for difficulty, entries := range other.Best {
+		for _, entry := range entries {
+			t.Best[difficulty] = insert(t.Best[difficulty], entry)
+		}
+	}
+}
+
+// insert adds entry to the sorted list unless it is already present and
+// trims the list to MaxEntries.
+func insert(entries []Entry, entry Entry) []Entry {
+	for _, e := range entries {
+		if e.same(entry) {
+			return entries
+		}
+	}
+	entries = append(entries, entry)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Millis < entries[j].Millis
+	})
+	if len(entries) > MaxEntries {
+		entries = entries[:MaxEntries]
+	}
+	return entries
+}
+
+// Format renders the table as text, one section per difficulty.
+func (t *Table) Format() string {
+	if len(t.Best) == 0 {
+		return "No best times yet."
+	}
+
+	difficulties := make([]string, 0, len(t.Best))
+	for difficulty := range t.Best {
+		difficulties = append(difficulties, difficulty)
+	}
+	sort.Strings(difficulties)
+
+	var b strings.Builder
+	b.WriteString("Best times:\n")
+	for _, difficulty := range difficulties {
+		fmt.Fprintf(&b, "  %s\n", difficulty)
+		for i, entry := range t.Best[difficulty] {
+			fmt.Fprintf(&b, "    %d. %-10s %s\n", i+1, entry.Duration().Round(time.Millisecond), entry.Date.Format("2006-01-02"))
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
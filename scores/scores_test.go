@@ -0,0 +1,119 @@
+package scores
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var day = time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)
+
+// times returns the completion times stored for difficulty, in seconds.
+func times(t *Table, difficulty string) []float64 {
+	var seconds []float64
+	for _, e := range t.Best[difficulty] {
+		seconds = append(seconds, e.Duration().Seconds())
+	}
+	return seconds
+}
+
+func TestAdd(t *testing.T) {
+	const difficulty = "10x10, 10 mines"
+	tests := []struct {
+		name     string
+		existing []int
+		add      int
+		rank     int
+		want     []float64
+	}{
+		{"empty table", nil, 30, 1, []float64{30}},
+		{"fastest", []int{20, 40}, 10, 1, []float64{10, 20, 40}},
+		{"in between", []int{20, 40}, 30, 2, []float64{20, 30, 40}},
+		{"tie ranks after the earlier time", []int{20, 40}, 20, 2, []float64{20, 20, 40}},
+		{"last place of a full table", []int{10, 20, 30, 40}, 50, 5, []float64{10, 20, 30, 40, 50}},
+		{"too slow for a full table", []int{10, 20, 30, 40, 50}, 60, 0, []float64{10, 20, 30, 40, 50}},
+		{"pushes the slowest out", []int{10, 20, 30, 40, 50}, 15, 2, []float64{10, 15, 20, 30, 40}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := &Table{Best: map[string][]Entry{}}
+			for i, seconds := range tt.existing {
+				table.Add(difficulty, time.Duration(seconds)*time.Second, day.AddDate(0, 0, -i-1))
+			}
+			if rank := table.Add(difficulty, time.Duration(tt.add)*time.Second, day); rank != tt.rank {
+				t.Errorf("rank = %d, want %d", rank, tt.rank)
+			}
+			if got := times(table, difficulty); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("times = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddKeepsDifficultiesApart(t *testing.T) {
+	table := &Table{Best: map[string][]Entry{}}
+	table.Add(Difficulty(9, 9, 10, false), 30*time.Second, day)
+	if rank := table.Add(Difficulty(9, 9, 10, true), 40*time.Second, day); rank != 1 {
+		t.Errorf("rank on a no-guess board = %d, want 1", rank)
+	}
+	if got := len(table.Best); got != 2 {
+		t.Errorf("%d difficulties, want 2", got)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	const difficulty = "9x9, 10 mines"
+	table := &Table{Best: map[string][]Entry{}}
+	table.Add(difficulty, 10*time.Second, day)
+	table.Add(difficulty, 30*time.Second, day)
+
+	other := &Table{Best: map[string][]Entry{}}
+	other.Add(difficulty, 10*time.Second, day) // the same result again
+	other.Add(difficulty, 10*time.Second, day.AddDate(0, 0, 1))
+	other.Add(difficulty, 20*time.Second, day)
+	other.Add(difficulty, 25*time.Second, day)
+	other.Add(difficulty, 40*time.Second, day)
+	other.Add("16x16, 40 mines", time.Minute, day)
+
+	table.Merge(other)
+	if got, want := times(table, difficulty), []float64{10, 10, 20, 25, 30}; !reflect.DeepEqual(got, want) {
+		t.Errorf("times = %v, want %v", got, want)
+	}
+	if got, want := times(table, "16x16, 40 mines"), []float64{60}; !reflect.DeepEqual(got, want) {
+		t.Errorf("times = %v, want %v", got, want)
+	}
+}
+
+func TestSaveMergesWithStoredTable(t *testing.T) {
+	const difficulty = "9x9, 10 mines"
+	path := filepath.Join(t.TempDir(), "dir", "scores.json")
+
+	empty, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(empty.Best) != 0 {
+		t.Fatalf("missing file loaded as %+v, want an empty table", empty.Best)
+	}
+
+	// Two games load the table before either of them saves a win.
+	first, _ := Load(path)
+	second, _ := Load(path)
+	first.Add(difficulty, 20*time.Second, day)
+	if err := first.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	second.Add(difficulty, 10*time.Second, day)
+	if err := second.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := times(stored, difficulty), []float64{10, 20}; !reflect.DeepEqual(got, want) {
+		t.Errorf("times = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+package update
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semver is a parsed semantic version, see https://semver.org.
+type semver struct {
+	major, minor, patch int
+	// prerelease holds the dot-separated identifiers after "-", if any.
+	prerelease []string
+}
+
+// parseSemver parses a version such as "1.2.3", "v1.2.3" or "1.3.0-rc.1".
+// Build metadata after "+" is ignored, as it does not affect precedence.
+func parseSemver(version string) (semver, error) {
+	s := strings.TrimPrefix(version, "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	var v semver
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		v.prerelease = strings.Split(s[i+1:], ".")
+		s = s[:i]
+		for _, id := range v.prerelease {
+			if id == "" {
+				return semver{}, fmt.Errorf("%q is not a semantic version", version)
+			}
+		}
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return semver{}, fmt.Errorf("%q is not a semantic version", version)
+	}
+	numbers := []*int{&v.major, &v.minor, &v.patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || part != strconv.Itoa(n) {
+			return semver{}, fmt.Errorf("%q is not a semantic version", version)
+		}
+		*numbers[i] = n
+	}
+	return v, nil
+}
+
+// compare returns -1, 0 or 1 as v is older than, equal to or newer than w.
+// A pre-release is older than the release it leads up to.
+func (v semver) compare(w semver) int {
+	for _, d := range [][2]int{{v.major, w.major}, {v.minor, w.minor}, {v.patch, w.patch}} {
+		if d[0] != d[1] {
+			return sign(d[0] - d[1])
+		}
+	}
+	switch {
+	case len(v.prerelease) == 0 && len(w.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(w.prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.prerelease) && i < len(w.prerelease); i++ {
+		if c := compareIdentifiers(v.prerelease[i], w.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return sign(len(v.prerelease) - len(w.prerelease))
+}
+
+// compareIdentifiers orders two pre-release identifiers: numeric ones by
+// value and before alphanumeric ones, which are ordered as strings.
+func compareIdentifiers(a, b string) int {
+	m, errA := strconv.Atoi(a)
+	n, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return sign(m - n)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
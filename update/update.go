@@ -0,0 +1,190 @@
+// Package update implements the self-update command: it looks up the latest
+// release, downloads the binary for the current platform, verifies it
+// against the published SHA-256 checksums and swaps it in place, rolling back
+// if anything goes wrong.
+package update
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// DefaultEndpoint is the GitHub API URL of the latest release.
+const DefaultEndpoint = "https://api.github.com/repos/dimaq12/minesweaper/releases/latest"
+
+// checksumsAsset is the name of the release asset listing "<sha256>  <file>" lines.
+const checksumsAsset = "checksums.txt"
+
+// ErrUpToDate is returned when the running binary is already the latest
+// release, or newer than it.
+var ErrUpToDate = errors.New("already up to date")
+
+type Release struct {
+	TagName string  `json:"tag_name"`
+	Assets  []Asset `json:"assets"`
+}
+
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+var client = &http.Client{Timeout: 60 * time.Second}
+
+// AssetName returns the release asset name of the binary for this platform.
+func AssetName() string {
+	name := fmt.Sprintf("minesweeper_%s_%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Run updates the running executable to the latest release published at
+// endpoint. It returns the new version, or ErrUpToDate if currentVersion is
+// already the latest one or newer, so a release is never downgraded. Both
+// versions must be semantic versions; a development build cannot tell
+// whether a release is newer and is not updated.
+func Run(endpoint, currentVersion string) (string, error) {
+	current, err := parseSemver(currentVersion)
+	if err != nil {
+		return "", fmt.Errorf("current version: %w; only release builds can be updated", err)
+	}
+	release, err := fetchRelease(endpoint)
+	if err != nil {
+		return "", err
+	}
+	latest, err := parseSemver(release.TagName)
+	if err != nil {
+		return "", fmt.Errorf("latest release: %w", err)
+	}
+	if latest.compare(current) <= 0 {
+		return release.TagName, ErrUpToDate
+	}
+
+	binaryURL, checksumsURL := "", ""
+	for _, asset := range release.Assets {
+		switch asset.Name {
+		case AssetName():
+			binaryURL = asset.URL
+		case checksumsAsset:
+			checksumsURL = asset.URL
+		}
+	}
+	if binaryURL == "" {
+		return "", fmt.Errorf("release %s has no %s asset", release.TagName, AssetName())
+	}
+	if checksumsURL == "" {
+		return "", fmt.Errorf("release %s has no %s asset", release.TagName, checksumsAsset)
+	}
+
+	checksums, err := download(checksumsURL)
+	if err != nil {
+		return "", fmt.Errorf("downloading checksums: %w", err)
+	}
+	want, err := findChecksum(checksums, AssetName())
+	if err != nil {
+		return "", err
+	}
+	binary, err := download(binaryURL)
+	if err != nil {
+		return "", fmt.Errorf("downloading binary: %w", err)
+	}
+	sum := sha256.Sum256(binary)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return "", fmt.Errorf("checksum mismatch for %s: got %s, want %s", AssetName(), got, want)
+	}
+
+	if err := replaceExecutable(binary); err != nil {
+		return "", err
+	}
+	return release.TagName, nil
+}
+
+func fetchRelease(endpoint string) (*Release, error) {
+	body, err := download(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("checking for updates: %w", err)
+	}
+	var release Release
+	if err := json.Unmarshal(body, &release); err != nil {
+		return nil, fmt.Errorf("decoding release info: %w", err)
+	}
+	return &release, nil
+}
+
+func download(url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// findChecksum looks up the hex SHA-256 of name in a checksums file.
+func findChecksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(strings.NewReader(string(checksums)))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum for %s", name)
+}
+
+// replaceExecutable swaps the running executable for binary. The old binary
+// is kept aside until the new one has been shown to start, and restored if
+// any step fails.
+func replaceExecutable(binary []byte) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locating executable: %w", err)
+	}
+	info, err := os.Stat(exe)
+	if err != nil {
+		return fmt.Errorf("locating executable: %w", err)
+	}
+
+	newPath, oldPath := exe+".new", exe+".old"
+	if err := os.WriteFile(newPath, binary, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("writing new binary: %w", err)
+	}
+	if err := os.Rename(exe, oldPath); err != nil {
+		os.Remove(newPath)
+		return fmt.Errorf("moving old binary aside: %w", err)
+	}
+	rollback := func(cause error) error {
+		if err := os.Rename(oldPath, exe); err != nil {
+			return fmt.Errorf("%v; rollback failed, previous binary left at %s: %w", cause, oldPath, err)
+		}
+		return fmt.Errorf("%v; previous binary restored", cause)
+	}
+	if err := os.Rename(newPath, exe); err != nil {
+		os.Remove(newPath)
+		return rollback(fmt.Errorf("installing new binary: %w", err))
+	}
+	// Make sure the new binary actually runs on this machine before committing to it.
+	if err := exec.Command(exe, "version").Run(); err != nil {
+		os.Remove(exe)
+		return rollback(fmt.Errorf("new binary failed to start: %w", err))
+	}
+
+	os.Remove(oldPath)
+	return nil
+}
@@ -0,0 +1,102 @@
+package update
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseSemver(t *testing.T) {
+	valid := []string{"1.2.3", "v1.2.3", "0.0.0", "1.3.0-rc.1", "1.2.3+build.5", "v2.0.0-beta+exp.sha.5114f85"}
+	for _, version := range valid {
+		if _, err := parseSemver(version); err != nil {
+			t.Errorf("parseSemver(%q): %v", version, err)
+		}
+	}
+	invalid := []string{"dev", "", "1.2", "1.2.3.4", "v1.x.3", "01.2.3", "1.2.3-", "1.2.3-rc..1", "-1.2.3"}
+	for _, version := range invalid {
+		if _, err := parseSemver(version); err == nil {
+			t.Errorf("parseSemver(%q) succeeded, want an error", version)
+		}
+	}
+}
+
+func TestSemverCompare(t *testing.T) {
+	// In increasing order of precedence, mostly taken from semver.org.
+	ordered := []string{
+		"0.9.9",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"1.10.0",
+		"2.0.0",
+	}
+	for i, a := range ordered {
+		for j, b := range ordered {
+			v, _ := parseSemver(a)
+			w, _ := parseSemver(b)
+			if got, want := v.compare(w), sign(i-j); got != want {
+				t.Errorf("compare(%s, %s) = %d, want %d", a, b, got, want)
+			}
+		}
+	}
+
+	v, _ := parseSemver("v1.2.3+build.1")
+	w, _ := parseSemver("1.2.3+build.2")
+	if c := v.compare(w); c != 0 {
+		t.Errorf("build metadata changed the order: compare = %d", c)
+	}
+}
+
+// serveRelease starts a release feed whose latest release has tag.
+func serveRelease(t *testing.T, tag string) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"tag_name": %q, "assets": []}`, tag)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestRunComparesVersions(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		latest  string
+		// wantErr is ErrUpToDate or a substring of the error.
+		wantErr interface{}
+	}{
+		{"same version", "1.2.0", "v1.2.0", ErrUpToDate},
+		{"older release", "1.10.0", "v1.9.0", ErrUpToDate},
+		{"pre-release of the running version", "1.2.0", "v1.2.0-rc.1", ErrUpToDate},
+		{"newer release", "1.9.0", "v1.10.0", "has no " + AssetName() + " asset"},
+		{"release of the running pre-release", "1.2.0-rc.1", "v1.2.0", "has no " + AssetName() + " asset"},
+		{"development build", "dev", "v1.2.0", "only release builds can be updated"},
+		{"unversioned release", "1.2.0", "latest", "latest release"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Run(serveRelease(t, tt.latest), tt.current)
+			switch want := tt.wantErr.(type) {
+			case error:
+				if !errors.Is(err, want) {
+					t.Errorf("err = %v, want %v", err, want)
+				}
+			case string:
+				if err == nil || errors.Is(err, ErrUpToDate) || !strings.Contains(err.Error(), want) {
+					t.Errorf("err = %v, want one containing %q", err, want)
+				}
+			}
+		})
+	}
+}
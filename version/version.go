@@ -0,0 +1,67 @@
+// Package version holds the build information of the game. The variables
+// are meant to be stamped at build time, for example:
+//
+//	go build -ldflags "-X github.com/dimaq12/minesweaper/version.Version=1.2.0 \
+//		-X github.com/dimaq12/minesweaper/version.Commit=$(git rev-parse --short HEAD) \
+//		-X github.com/dimaq12/minesweaper/version.BuildDate=$(date -u +%Y-%m-%d)"
+package version
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+)
+
+var (
+	// Version is the semantic version of the release.
+	Version = "dev"
+	// Commit is the git commit the binary was built from.
+	Commit = ""
+	// BuildDate is the date the binary was built.
+	BuildDate = ""
+	// Features is a comma separated list of optional features enabled in the build.
+	Features = ""
+)
+
+// Info returns a multi-line, human readable description of the build.
+func Info() string {
+	commit, date := Commit, BuildDate
+	// Fall back to the VCS data the Go toolchain embeds when no ldflags were given.
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && commit == "":
+				commit = setting.Value
+				if len(commit) > 7 {
+					commit = commit[:7]
+				}
+			case setting.Key == "vcs.time" && date == "":
+				date = setting.Value
+			}
+		}
+	}
+	if commit == "" {
+		commit = "unknown"
+	}
+	if date == "" {
+		date = "unknown"
+	}
+
+	features := "none"
+	if list := FeatureList(); len(list) > 0 {
+		features = strings.Join(list, ", ")
+	}
+
+	return fmt.Sprintf("minesweeper %s\ncommit: %s\nbuilt: %s\nfeatures: %s", Version, commit, date, features)
+}
+
+// FeatureList returns the enabled features as a slice.
+func FeatureList() []string {
+	var list []string
+	for _, feature := range strings.Split(Features, ",") {
+		if feature = strings.TrimSpace(feature); feature != "" {
+			list = append(list, feature)
+		}
+	}
+	return list
+}